@@ -0,0 +1,72 @@
+// advancementlist はサーバーのjarから advancementlist.yml を生成する
+//
+//	go run ./cmd/advancementlist -jar server.jar -out config/advancementlist.yml
+//
+// 出力先に既存の advancementlist.yml がある場合は、アイコンの設定を引き継ぐ
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"com.oykdn.mc-advancement-collector/config"
+)
+
+func main() {
+	var (
+		jar = flag.String("jar", "", "server jar (vanilla / Paper)")
+		out = flag.String("out", config.ADVANCEMENTLIST_PATH, "output path (- for stdout)")
+	)
+	flag.Parse()
+
+	if *jar == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*jar, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(jar, out string) error {
+	list, err := config.ReadAdvancementJar(jar)
+	if err != nil {
+		return err
+	}
+
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	if out != "-" {
+		if icons, err := config.LoadAdvancementList(out); err == nil {
+			list.MergeIcons(icons)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	b, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	if out == "-" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+
+	if err := os.WriteFile(out, b, 0644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%d advancements written to %s\n", len(list.Advancements), out)
+	return nil
+}
@@ -0,0 +1,195 @@
+// extractlang はゲームのファイルから言語ファイルを取り出し、lang/ に配置する
+//
+//	go run ./cmd/extractlang -assets ~/.minecraft/assets -jar ~/.minecraft/versions/1.20.4/1.20.4.jar
+//
+// -assets には .minecraft/assets (indexes/ のインデックスと objects/ のハッシュ名のファイル) を、
+// -jar にはクライアントの jar を指定する (en_us は jar にのみ含まれるため、両方の指定を推奨)
+//
+// 取り出した後、進捗の一覧の languageKey が全ての言語で引けるかを確認する
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/config"
+	"com.oykdn.mc-advancement-collector/lang"
+)
+
+func main() {
+	var (
+		assets    = flag.String("assets", "", ".minecraft/assets directory")
+		index     = flag.String("index", "", "asset index name (default: the newest in assets/indexes)")
+		jar       = flag.String("jar", "", "client jar")
+		languages = flag.String("lang", "", "comma separated language codes to extract (default: all)")
+		out       = flag.String("out", lang.LANG_PATH, "output directory")
+		verify    = flag.Bool("verify", true, "verify that every languageKey in the advancement list resolves")
+	)
+	flag.Parse()
+
+	if *assets == "" && *jar == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var codes []string
+	if *languages != "" {
+		codes = strings.Split(strings.ToLower(*languages), ",")
+	}
+
+	files, err := extract(*assets, *index, *jar, codes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := write(files, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if !*verify {
+		return
+	}
+
+	ok, err := verifyKeys(files)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// extract は assets と jar から言語ファイルを取り出す (両方にある場合は jar を優先)
+func extract(assets, index, jar string, codes []string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	if assets != "" {
+		extracted, err := lang.ExtractAssets(assets, index)
+		if err != nil {
+			return nil, err
+		}
+		for code, b := range extracted {
+			files[code] = b
+		}
+	}
+
+	if jar != "" {
+		extracted, err := lang.ExtractJar(jar)
+		if err != nil {
+			return nil, err
+		}
+		for code, b := range extracted {
+			files[code] = b
+		}
+	}
+
+	if len(codes) > 0 {
+		selected := make(map[string][]byte, len(codes))
+		for _, code := range codes {
+			code = strings.TrimSpace(code)
+			b, exists := files[code]
+			if !exists {
+				return nil, fmt.Errorf("language not found: %s", code)
+			}
+			selected[code] = b
+		}
+		files = selected
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no language files found")
+	}
+
+	return files, nil
+}
+
+func write(files map[string][]byte, out string) error {
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	for code, b := range files {
+		if _, err := lang.ParseLang(b); err != nil {
+			return fmt.Errorf("%s: %w", code, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(out, code+".json"), b, 0644); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d language files written to %s\n", len(files), out)
+	return nil
+}
+
+// verifyKeys は進捗の一覧 (config.yml の設定で読み込んだもの) の languageKey が各言語で引けるかを確認する
+func verifyKeys(files map[string][]byte) (bool, error) {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		return false, fmt.Errorf("failed to load advancement list: %w", err)
+	}
+
+	keys := translateKeys(conf.AdvancementList)
+
+	codes := make([]string, 0, len(files))
+	for code := range files {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	ok := true
+	for _, code := range codes {
+		l, err := lang.ParseLang(files[code])
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", code, err)
+		}
+
+		var missing []string
+		for _, key := range keys {
+			if _, exists := l.Mapping[key]; !exists {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) == 0 {
+			fmt.Fprintf(os.Stderr, "%s: ok (%d keys)\n", code, len(keys))
+			continue
+		}
+
+		ok = false
+		fmt.Fprintf(os.Stderr, "%s: %d of %d keys missing\n", code, len(missing), len(keys))
+		for _, key := range missing {
+			fmt.Fprintf(os.Stderr, "  %s\n", key)
+		}
+	}
+
+	return ok, nil
+}
+
+// translateKeys は languageKey から引く翻訳キーを返す
+// - タイトル・説明をテキストコンポーネントで指定している進捗 (データパック) はその項目を除く
+func translateKeys(list *config.AdvancementList) []string {
+	var keys []string
+	for _, v := range list.Advancements {
+		if v.LanguageKey == "" {
+			continue
+		}
+
+		if v.Title == nil {
+			keys = append(keys, v.LanguageKey+lang.LANG_SUFFIX_TITLE)
+		}
+		if v.Description == nil {
+			keys = append(keys, v.LanguageKey+lang.LANG_SUFFIX_DESCRIPTION)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	DefaultAggregateInterval = 60 * time.Second
+)
+
+type playerSnapshot struct {
+	Profile model.PlayerProfile
+	Summary *model.PlayerAdvancementSummary
+}
+
+// aggregate は全プレイヤーの進捗をバックグラウンドで集計した結果を保持する
+// - ランキング等のリクエストごとに全ファイルを読み直さないようにする
+type aggregate struct {
+	mu      sync.RWMutex
+	players []playerSnapshot
+	stats   map[string]model.AdvancementStats
+	updated time.Time
+
+	trigger chan struct{}
+}
+
+func newAggregate() *aggregate {
+	return &aggregate{
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Trigger は次の定期実行を待たずに再集計させる
+func (a *aggregate) Trigger() {
+	select {
+	case a.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (a *aggregate) Snapshot() ([]playerSnapshot, time.Time) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.players, a.updated
+}
+
+// Stats は進捗ごとの達成状況を返す (集計前は空)
+func (a *aggregate) Stats() map[string]model.AdvancementStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.stats
+}
+
+func (c collector) runAggregate(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultAggregateInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.refreshAggregate()
+
+		select {
+		case <-ticker.C:
+		case <-c.aggregate.trigger:
+		}
+	}
+}
+
+func (c collector) refreshAggregate() {
+	p, err := c.Player(model.PlayersQuery{})
+	if err != nil {
+		logger.Warn(err)
+		return
+	}
+
+	players := make([]playerSnapshot, 0, len(p.Players))
+	for _, player := range p.Players {
+		// キャッシュが有効なプレイヤーは再読込されない
+		summary, err := c.Load(player.Id)
+		if err != nil {
+			logger.Warn(err)
+			continue
+		}
+
+		players = append(players, playerSnapshot{
+			Profile: player.PlayerProfile,
+			Summary: summary,
+		})
+	}
+
+	// 順序を固定しておく
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Profile.Id < players[j].Profile.Id
+	})
+
+	stats := c.computeStats(players)
+
+	c.aggregate.mu.Lock()
+	defer c.aggregate.mu.Unlock()
+
+	c.aggregate.players = players
+	c.aggregate.stats = stats
+	c.aggregate.updated = time.Now().UTC()
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"errors"
+	"image"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/profile"
+	"com.oykdn.mc-advancement-collector/skin"
+)
+
+const (
+	DefaultSkinPath   = "./data/skins"
+	DefaultSkinTTL    = time.Hour
+	DefaultAvatarSize = 64
+)
+
+// Avatar はプレイヤーのスキンから顔・胸から上・全身の画像を描く
+// - スキンが取得できない場合は既定のスキン (Steve / Alex) を使う
+func (c collector) Avatar(userId string, query model.AvatarQuery) image.Image {
+	size := query.Size
+	if size <= 0 {
+		size = DefaultAvatarSize
+	}
+
+	texture, slim := c.playerSkin(userId)
+
+	switch query.View {
+	case model.AvatarViewBust:
+		return skin.Bust(texture, slim, size)
+	case model.AvatarViewBody:
+		return skin.Body(texture, slim, size)
+	}
+
+	return skin.Face(texture, size)
+}
+
+func (c collector) playerSkin(userId string) (image.Image, bool) {
+	if meta := c.skinMeta(userId); meta != nil {
+		texture, err := c.skins.Load(meta.Url)
+		if err == nil {
+			return texture, meta.Slim
+		}
+		logger.Warn(err)
+	}
+
+	return skin.Default(userId)
+}
+
+// skinMeta はスキンのURLとモデルを返す
+// - プロフィールAPIの結果は skinTTL の間キャッシュする (スキン未設定の場合も含む)
+// - 名前の解決でAPIを呼んだ場合は、その時のスキンを使う (fetchProfile)
+func (c collector) skinMeta(userId string) *model.PlayerSkin {
+	// オフラインモード・Floodgate のプレイヤーはプロフィールAPIにスキンが無い
+	if c.provider == nil || c.skins == nil || profile.AccountTypeOf(userId) != model.AccountOnline {
+		return nil
+	}
+
+	if cache, exists := c.skinCache.Get(userId); exists {
+		if time.Now().Before(cache.Updated.Add(c.skinTTL)) {
+			return cache.Response
+		}
+	}
+
+	p, err := c.fetchProfile(userId)
+	if err != nil {
+		// 一時的なエラーはキャッシュしない
+		if !errors.Is(err, profile.ErrProfileNotFound) {
+			logger.Warn(err)
+		}
+		return nil
+	}
+
+	// 取得した名前も記録しておく
+	c.observe(model.PlayerProfile{
+		Id:   userId,
+		Name: p.Name,
+	}, true)
+
+	return p.Skin
+}
+
+// fetchProfile はプロフィールAPIで問い合わせ、レスポンスに含まれるスキンもキャッシュする
+// - 存在しないプレイヤーはスキン無しとしてキャッシュする
+func (c collector) fetchProfile(userId string) (*model.PlayerProfile, error) {
+	p, err := c.provider.Profile(userId)
+	if c.skins != nil {
+		switch {
+		case err == nil:
+			c.skinCache.Set(userId, p.Skin, time.Now())
+		case errors.Is(err, profile.ErrProfileNotFound):
+			c.skinCache.Set(userId, nil, time.Now())
+		}
+	}
+
+	return p, err
+}
@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry[T any] struct {
+	Response T
+	Updated  time.Time
+}
+
+// entryCache はプレイヤーごとの集計結果を保持する
+// - HTTPリクエストとファイル監視の両方から更新されるため、排他制御する
+type entryCache[T any] struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry[T]
+}
+
+func newEntryCache[T any]() *entryCache[T] {
+	return &entryCache[T]{
+		entries: make(map[string]cacheEntry[T]),
+	}
+}
+
+func (ec *entryCache[T]) Get(userId string) (cacheEntry[T], bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+
+	entry, exists := ec.entries[userId]
+	return entry, exists
+}
+
+func (ec *entryCache[T]) Set(userId string, resp T, updated time.Time) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	ec.entries[userId] = cacheEntry[T]{
+		Response: resp,
+		Updated:  updated,
+	}
+}
+
+func (ec *entryCache[T]) Delete(userId string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	delete(ec.entries, userId)
+}
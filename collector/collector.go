@@ -2,29 +2,30 @@ package collector
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"image"
 	"math"
 	"net/http"
 	"os"
 	"path"
-	"path/filepath"
-	"strings"
+	"runtime"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"com.oykdn.mc-advancement-collector/config"
+	"com.oykdn.mc-advancement-collector/history"
 	"com.oykdn.mc-advancement-collector/lang"
 	_logger "com.oykdn.mc-advancement-collector/logger"
 	"com.oykdn.mc-advancement-collector/model"
 	"com.oykdn.mc-advancement-collector/model/responses"
+	"com.oykdn.mc-advancement-collector/profile"
+	"com.oykdn.mc-advancement-collector/skin"
 )
 
 const (
-	PROFILE_API_PATH = "https://sessionserver.mojang.com/session/minecraft/profile"
-
 	MinecraftAdvancementTimeLayout = "2006-01-02 15:04:05 -0700"
 
 	SpriteSize = 32
@@ -32,94 +33,137 @@ const (
 
 var (
 	ErrPlayerNotFound      = fmt.Errorf("player not found")
+	ErrPlayerNameNotFound  = fmt.Errorf("player name not found")
+	ErrPlayerNameAmbiguous = fmt.Errorf("player name is ambiguous")
 	ErrOpenAdvancementJSON = fmt.Errorf("failed to open advancement json")
 	ErrParseAdvancement    = fmt.Errorf("failed to parse advancement")
 
 	ErrAdvancementKeyNotFound = fmt.Errorf("advancement key not found")
 	ErrAdvancementConvert     = fmt.Errorf("failed to convert advancement")
+
+	ErrHistoryDisabled = fmt.Errorf("history is disabled")
+
+	ErrStatsNotFound = fmt.Errorf("player stats not found")
+	ErrOpenStatsJSON = fmt.Errorf("failed to open stats json")
+	ErrParseStats    = fmt.Errorf("failed to parse stats")
+
+	ErrPlayerDataNotFound = fmt.Errorf("player data not found")
+	ErrParsePlayerData    = fmt.Errorf("failed to parse player data")
 )
 
 type Collector interface {
-	Player() (*responses.PlayersResponse, error)
+	Player(model.PlayersQuery) (*responses.PlayersResponse, error)
+	ResolvePlayerId(string) (string, error)
 	Load(string) (*model.PlayerAdvancementSummary, error)
-	Filter(model.AdvancementFilterCondition, *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary
+	At(time.Time, *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary
+	Filter(model.AdvancementFilter, *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary
+	Text(string, model.TextFormat, *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary
 	Response(*model.PlayerAdvancementSummary) *responses.PlayerAdvancementResponse
+	Tree(*model.PlayerAdvancementSummary) *responses.PlayerAdvancementTreeResponse
+	History(string, model.AdvancementHistoryQuery) (*responses.AdvancementHistoryResponse, error)
+	Leaderboard(model.LeaderboardQuery) *responses.LeaderboardResponse
+	AdvancementStats() *responses.AdvancementStatsResponse
+	Compare([]string) (*responses.CompareResponse, error)
+	Diagnostics() *responses.DiagnosticsResponse
+	LoadStats(string) (*model.PlayerStatsSummary, error)
+	StatsResponse(string, []string, *model.PlayerStatsSummary) *responses.PlayerStatsResponse
+	PlayerData(string) (*model.PlayerDataProfile, error)
+	Avatar(string, model.AvatarQuery) image.Image
 }
 
 type collector struct {
-	basePath string
-
-	ref  map[string]config.AdvancementRecord
-	lang map[string]string
-
-	playercache config.PlayerCache
-	cacheSecond int
-	cache       map[string]struct {
-		Response model.PlayerAdvancementSummary
-		Updated  time.Time
-	}
+	basePath       string
+	statsPath      string
+	playerdataPath string
+	showPosition   bool
+
+	ref       map[string]config.AdvancementRecord
+	languages *lang.Languages
+	// 既定の言語 (キャッシュする進捗・統計の名前はこの言語)
+	lang *lang.Lang
+
+	usercache      *userCache
+	playercache    config.PlayerCache
+	playercacheMu  *sync.Mutex
+	usePlayerCache bool
+	playercacheTTL time.Duration
+	provider       profile.Provider
+	concurrency    int
+
+	floodgatePrefix string
+	floodgateXUIDs  map[string]string
+
+	skins     *skin.Store
+	skinCache *entryCache[*model.PlayerSkin]
+	skinTTL   time.Duration
+
+	cacheSecond     int
+	cache           *entryCache[model.PlayerAdvancementSummary]
+	statsCache      *entryCache[model.PlayerStatsSummary]
+	playerdataCache *entryCache[model.PlayerDataProfile]
+
+	watcher      *watcher
+	statsWatcher *watcher
+	history      *history.Store
+	aggregate    *aggregate
 }
 
 var logger *_logger.ZapLogger = _logger.NewZapLogger()
 
-func (c collector) Player() (*responses.PlayersResponse, error) {
+func (c collector) Player(query model.PlayersQuery) (*responses.PlayersResponse, error) {
 	// 進捗フォルダ以下のUUID.jsonをスキャン
-	files, err := os.ReadDir(c.basePath)
+	uuids, err := c.playerIds()
 	if err != nil {
 		return nil, err
 	}
 
-	var uuids []string
-	for _, f := range files {
-		_, basename := filepath.Split(f.Name())
-		uuids = append(uuids, strings.Split(basename, ".")[0])
-	}
+	// 期限切れの usercache.json の名前は、一括APIでまとめて確認しておく
+	c.verifyStaleNames(uuids)
 
 	// uuidからプレイヤー名を取得
-	var players []model.PlayerProfile
+	// - APIへの同時リクエスト数を抑えるため、並列数を制限する
+	// - 進捗で並び替える場合は、指定が無くても集計する
+	withSummary := query.Summary || query.Sort == model.PlayerSortProgress || query.Sort == model.PlayerSortActivity
+	language := c.languages.Get(query.Language)
+	players := make([]model.PlayerListEntry, 0, len(uuids))
+	playerIds := make([]string, 0, len(uuids))
 
 	eg := new(errgroup.Group)
+	eg.SetLimit(c.concurrency)
 	var mu sync.Mutex
 	for _, id := range uuids {
 		id := id
 
 		eg.Go(func() error {
-			// キャッシュに存在した場合はキャッシュから返却
-			cache, exists := c.playercache.Players[id]
-			if exists {
-				// レスポンスとキャッシュに書き込み
-				mu.Lock()
-				defer mu.Unlock()
-
-				players = append(players, cache)
-				return nil
-			}
-
-			// Mojang APIでUUIDからProfileを得る
-			profile, err := c.fetchPlayerProfile(id)
+			p, err := c.resolveProfile(id, true)
 			if err != nil {
-				logger.Warn(err)
+				// 存在しないUUID (ネガティブキャッシュ中を含む) は毎回の一覧で出るため、通信エラー等のみ警告する
+				if errors.Is(err, profile.ErrProfileNotFound) {
+					logger.Debugf("profile not found: %s", id)
+				} else {
+					logger.Warn(err)
+				}
 			}
 
-			if profile == nil {
-				return nil
+			// 名前が解決できない場合もUUIDのみで一覧に含める
+			if p == nil {
+				unresolved := unresolvedProfile(id)
+				p = &unresolved
 			}
 
-			p := model.PlayerProfile{
-				Id:   id,
-				Name: profile.Name,
+			// 名前の指定がある場合は、以前の名前も含めて一致するプレイヤーのみ
+			if query.Name != "" && !p.MatchName(query.Name) {
+				return nil
 			}
 
-			// レスポンスとキャッシュに書き込み
+			// レスポンスに書き込み
 			mu.Lock()
 			defer mu.Unlock()
 
-			players = append(players, p)
-
-			c.playercache.Players[id] = p
-			if err := c.playercache.Save(config.PLAYERCACHE_PATH); err != nil {
-				logger.Warn(err)
-			}
+			players = append(players, model.PlayerListEntry{
+				PlayerProfile: *p,
+			})
+			playerIds = append(playerIds, id)
 
 			return nil
 		})
@@ -129,19 +173,63 @@ func (c collector) Player() (*responses.PlayersResponse, error) {
 		return nil, err
 	}
 
+	// 進捗の集計はローカルのファイルの読み込みのため、APIの並列数とは別に CPU 数で制限する
+	if withSummary {
+		eg := new(errgroup.Group)
+		eg.SetLimit(runtime.GOMAXPROCS(0))
+		for i := range players {
+			entry, id := &players[i], playerIds[i]
+
+			eg.Go(func() error {
+				entry.Summary = c.playerListSummary(id, language)
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return nil, err
+		}
+	}
+
+	sortPlayers(players, query.Sort, query.Order)
+
+	total := len(players)
+	limit := query.Limit
+	if limit <= 0 {
+		limit = total
+	}
+	start, end := paginate(total, query.Offset, limit)
+
 	return &responses.PlayersResponse{
-		Players: players,
+		Players: players[start:end],
+		Total:   total,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
 	}, nil
 }
 
+// cachedProfile はAPIを呼ばずに解決できるプレイヤー名を返す (未取得の場合はIDのみ)
+func (c collector) cachedProfile(userId string) model.PlayerProfile {
+	if p, _ := c.resolveProfile(userId, false); p != nil {
+		return *p
+	}
+
+	return unresolvedProfile(userId)
+}
+
 func (c collector) Load(userId string) (*model.PlayerAdvancementSummary, error) {
-	// キャッシュが存在 かつ 時間内の場合はキャッシュから返す
-	if cache, exists := c.cache[userId]; exists {
-		if time.Now().Before(cache.Updated.Add(time.Duration(c.cacheSecond) * time.Second)) {
+	// キャッシュが存在 かつ 有効な場合はキャッシュから返す
+	// - ファイル監視中は変更通知で更新されるため、時間では失効させない
+	if cache, exists := c.cache.Get(userId); exists {
+		if c.watcher.Active() || time.Now().Before(cache.Updated.Add(time.Duration(c.cacheSecond)*time.Second)) {
 			return &cache.Response, nil
 		}
 	}
 
+	return c.build(userId)
+}
+
+func (c collector) build(userId string) (*model.PlayerAdvancementSummary, error) {
 	// jsonから進捗をロード
 	var (
 		filepath = path.Join(c.basePath, userId+".json")
@@ -189,22 +277,35 @@ func (c collector) Load(userId string) (*model.PlayerAdvancementSummary, error)
 	resp := model.PlayerAdvancementSummary{
 		Advancements: advancements,
 		Progress:     *c.summarize(advancements),
+		Tabs:         c.summarizeTabs(advancements),
 		Updated:      *updated,
 		Cached:       now,
 	}
 
 	// - キャッシュ更新
-	c.cache[userId] = struct {
-		Response model.PlayerAdvancementSummary
-		Updated  time.Time
-	}{
-		Response: resp,
-		Updated:  now,
+	c.cache.Set(userId, resp, now)
+
+	// - 前回からの変化を履歴に記録
+	if c.history != nil {
+		if err := c.history.Record(userId, advancements, now); err != nil {
+			logger.Warn(err)
+		}
 	}
 
 	return &resp, nil
 }
 
+// onAdvancementChanged はファイル監視から呼ばれ、該当プレイヤーのキャッシュのみ再構築する
+func (c collector) onAdvancementChanged(userId string) {
+	if _, err := c.build(userId); err != nil {
+		// 削除された・読み込めない場合はキャッシュを破棄し、次回リクエスト時に再読込させる
+		logger.Debugf("invalidate advancement cache: %s: %v", userId, err)
+		c.cache.Delete(userId)
+	}
+
+	c.aggregate.Trigger()
+}
+
 func (c collector) load(filepath string) (map[string]*model.MinecraftAdvancement, *time.Time, error) {
 	// JSONファイル存在確認 -> オープン
 	fileinfo, err := os.Stat(filepath)
@@ -268,25 +369,29 @@ func (c collector) convert(key string, original *model.MinecraftAdvancement) (*m
 		criteria[k] = &t
 	}
 
-	// 進捗集計
-	total := 0
-	switch ref.Metrics {
-	case model.MetricsAnyOf:
-		total = 1
-	case model.MetricsAllOf:
-		total = len(ref.Criteria)
-	}
+	// 設定に無いcriteria (旧バージョンで改名された, データパック独自 等) は別枠で返す
+	// - 進捗の集計には含めない
+	var extra map[string]*time.Time
+	for k, timestamp := range original.Criteria {
+		if _, exists := criteria[k]; exists {
+			continue
+		}
 
-	var percentage float64 = 0
-	count := len(original.Criteria)
-	if original.Done {
-		count = total
-		percentage = 1
-	} else if len(original.Criteria) > 0 {
-		percentage = float64(count) / float64(total)
-		percentage = math.Floor(percentage*1000) / 1000
+		if extra == nil {
+			extra = make(map[string]*time.Time)
+		}
+
+		t, err := time.Parse(MinecraftAdvancementTimeLayout, timestamp)
+		if err != nil {
+			extra[k] = nil
+			continue
+		}
+		extra[k] = &t
 	}
 
+	// 進捗集計
+	progress := c.progress(ref, criteria, original.Done)
+
 	// アイコン表示
 	var posx, posy *int
 	if ref.Icon.InvSprite {
@@ -297,12 +402,19 @@ func (c collector) convert(key string, original *model.MinecraftAdvancement) (*m
 		posy = &y
 	}
 
+	// タイトル・説明
+	title, description := c.displayText(c.lang, key, ref)
+
 	return &model.PlayerAdvancement{
 		Key:    key,
 		Parent: ref.Parent,
 		Display: model.PlayerAdvancementDisplay{
-			Title:       c.lang[ref.LanguageKey+lang.LANG_SUFFIX_TITLE],
-			Description: c.lang[ref.LanguageKey+lang.LANG_SUFFIX_DESCRIPTION],
+			Title:       lang.Plain(title),
+			Description: lang.Plain(description),
+			Segments: &model.PlayerAdvancementDisplaySegments{
+				Title:       title,
+				Description: description,
+			},
 			Icon: model.PlayerAdvancementDisplayIcon{
 				Url:       ref.Icon.Url,
 				InvSprite: ref.Icon.InvSprite,
@@ -310,19 +422,54 @@ func (c collector) convert(key string, original *model.MinecraftAdvancement) (*m
 				PosY:      posy,
 			},
 		},
-		Type:     ref.Type,
-		Hidden:   ref.Hidden,
-		Done:     original.Done,
-		Metrics:  ref.Metrics,
-		Criteria: criteria,
-		Progress: model.AdvancementProgress{
-			Total:      total,
-			Done:       count,
-			Percentage: percentage,
-		},
+		Type:          ref.Type,
+		Tab:           ref.Tab,
+		Namespace:     ref.Namespace,
+		Source:        ref.Source,
+		Hidden:        ref.Hidden,
+		Done:          original.Done,
+		Metrics:       ref.Metrics,
+		Criteria:      criteria,
+		ExtraCriteria: extra,
+		Progress:      progress,
 	}, nil
 }
 
+// progress はゲーム内と同様に、requirements のうち満たしたグループ数で進捗を求める
+func (c collector) progress(ref config.AdvancementRecord, criteria map[string]*time.Time, done bool) model.AdvancementProgress {
+	requirements := ref.RequirementGroups()
+	total := len(requirements)
+
+	if done {
+		return model.AdvancementProgress{
+			Total:      total,
+			Done:       total,
+			Percentage: 1,
+		}
+	}
+
+	count := 0
+	for _, group := range requirements {
+		for _, k := range group {
+			if criteria[k] != nil {
+				count += 1
+				break
+			}
+		}
+	}
+
+	var percentage float64 = 0
+	if total > 0 {
+		percentage = math.Floor((float64(count)/float64(total))*1000) / 1000
+	}
+
+	return model.AdvancementProgress{
+		Total:      total,
+		Done:       count,
+		Percentage: percentage,
+	}
+}
+
 func (c collector) summarize(advancements map[string]*model.PlayerAdvancement) *model.AdvancementProgress {
 	total := len(advancements)
 
@@ -336,22 +483,46 @@ func (c collector) summarize(advancements map[string]*model.PlayerAdvancement) *
 			continue
 		}
 
-		if len(v.Criteria) > 0 {
+		if v.Progress.Total > 0 {
 			progress += float64(v.Progress.Done) / float64(v.Progress.Total)
 		}
 	}
 
+	// 対象が無い場合は0除算を避ける
+	percentage := 0.0
+	if total > 0 {
+		percentage = math.Floor((progress/float64(total))*1000) / 1000
+	}
+
 	return &model.AdvancementProgress{
 		Total:      len(advancements),
 		Done:       done,
-		Percentage: math.Floor((progress/float64(total))*1000) / 1000,
+		Percentage: percentage,
 	}
 }
 
-func (c collector) Filter(condition model.AdvancementFilterCondition, summary *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary {
+// summarizeTabs はタブごとに進捗を集計する
+func (c collector) summarizeTabs(advancements map[string]*model.PlayerAdvancement) map[string]model.AdvancementProgress {
+	tabs := make(map[string]map[string]*model.PlayerAdvancement)
+	for k, v := range advancements {
+		if _, exists := tabs[v.Tab]; !exists {
+			tabs[v.Tab] = make(map[string]*model.PlayerAdvancement)
+		}
+		tabs[v.Tab][k] = v
+	}
+
+	progress := make(map[string]model.AdvancementProgress, len(tabs))
+	for tab, v := range tabs {
+		progress[tab] = *c.summarize(v)
+	}
+
+	return progress
+}
+
+func (c collector) Filter(filter model.AdvancementFilter, summary *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary {
 	advancements := make(map[string]*model.PlayerAdvancement)
 
-	switch condition {
+	switch filter.Condition {
 	case model.ConditionDone:
 		for k, v := range summary.Advancements {
 			if v.Done {
@@ -403,65 +574,170 @@ func (c collector) Filter(condition model.AdvancementFilterCondition, summary *m
 		}
 	}
 
+	// タブの指定がある場合は、そのタブの進捗のみ
+	if filter.Tab != "" {
+		for k, v := range advancements {
+			if v.Tab != filter.Tab {
+				delete(advancements, k)
+			}
+		}
+	}
+
 	return &model.PlayerAdvancementSummary{
 		Advancements: advancements,
 		Progress:     summary.Progress,
+		Tabs:         summary.Tabs,
 		Updated:      summary.Updated,
 		Cached:       summary.Cached,
 	}
 }
 
 func (c collector) Response(summary *model.PlayerAdvancementSummary) *responses.PlayerAdvancementResponse {
+	// 全プレイヤーの集計が済んでいれば、達成率(レア度)を付与する
+	// - キャッシュ上の進捗は共有されているため、コピーに設定する
+	players, _ := c.aggregate.Snapshot()
+	stats := c.aggregate.Stats()
+
 	advancements := make([]*model.PlayerAdvancement, 0, len(summary.Advancements))
-	for _, v := range summary.Advancements {
+	for k, v := range summary.Advancements {
+		if s, exists := stats[k]; exists && len(players) > 0 {
+			adv := *v
+			rarity := s.Percentage
+			adv.Rarity = &rarity
+			v = &adv
+		}
+
 		advancements = append(advancements, v)
 	}
 
 	return &responses.PlayerAdvancementResponse{
 		Advancements: advancements,
 		Progress:     summary.Progress,
+		Tabs:         summary.Tabs,
 		Updated:      summary.Updated,
 		Cached:       summary.Cached,
 	}
 }
 
-func (c collector) fetchPlayerProfile(id string) (*model.PlayerProfile, error) {
-	resp, err := http.DefaultClient.Get(fmt.Sprintf("%s/%s", PROFILE_API_PATH, id))
-	if err != nil {
-		return nil, err
+func (c collector) History(userId string, query model.AdvancementHistoryQuery) (*responses.AdvancementHistoryResponse, error) {
+	if c.history == nil {
+		return nil, ErrHistoryDisabled
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	// 最新の状態を記録してから返す
+	if _, err := c.Load(userId); err != nil {
 		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	events, total, err := c.history.Timeline(userId, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var profile model.MojangPlayerProfile
-	if err := json.Unmarshal(body, &profile); err != nil {
-		return nil, err
+	limit := query.Limit
+	if limit <= 0 {
+		limit = history.DefaultLimit
 	}
 
-	return &model.PlayerProfile{
-		Id:   profile.Id,
-		Name: profile.Name,
+	return &responses.AdvancementHistoryResponse{
+		Events: events,
+		Total:  total,
+		Limit:  limit,
+		Offset: query.Offset,
 	}, nil
 }
 
-func NewCollector(config *config.AppConfig, list *config.AdvancementList, lang *lang.Lang, playercache *config.PlayerCache) Collector {
-	return &collector{
-		basePath:    config.AdvancementPath,
-		ref:         list.Advancements,
-		lang:        lang.Mapping,
-		playercache: *playercache,
-		cacheSecond: config.Cache,
-		cache: make(map[string]struct {
-			Response model.PlayerAdvancementSummary
-			Updated  time.Time
-		}),
+func NewCollector(config *config.AppConfig, list *config.AdvancementList, languages *lang.Languages, playercache *config.PlayerCache) Collector {
+	c := &collector{
+		basePath:        config.AdvancementPath,
+		statsPath:       config.StatsDir(),
+		playerdataPath:  config.PlayerDataDir(),
+		showPosition:    config.Profile.ShowPosition,
+		ref:             list.Advancements,
+		languages:       languages,
+		lang:            languages.Get(languages.Default),
+		playercache:     *playercache,
+		playercacheMu:   &sync.Mutex{},
+		usePlayerCache:  config.Players.PlayerCache.IsEnabled(),
+		playercacheTTL:  time.Duration(config.Players.PlayerCache.TTL) * time.Second,
+		concurrency:     config.Players.Remote.Concurrency,
+		floodgatePrefix: config.Players.Floodgate.UsernamePrefix(),
+		floodgateXUIDs:  config.Players.Floodgate.XUIDs,
+		cacheSecond:     config.Cache,
+		cache:           newEntryCache[model.PlayerAdvancementSummary](),
+		statsCache:      newEntryCache[model.PlayerStatsSummary](),
+		playerdataCache: newEntryCache[model.PlayerDataProfile](),
+		skinCache:       newEntryCache[*model.PlayerSkin](),
+		skinTTL:         time.Duration(config.Avatar.TTL) * time.Second,
+		aggregate:       newAggregate(),
+	}
+
+	if c.concurrency <= 0 {
+		c.concurrency = DefaultConcurrency
+	}
+
+	// プロフィールAPI (設定が不正な場合はAPIを使わない)
+	if config.Players.Remote.IsEnabled() {
+		provider, err := profile.NewFromConfig(config.Players.Remote)
+		if err != nil {
+			logger.Warnf("failed to configure profile provider: %v", err)
+		} else {
+			c.provider = provider
+		}
 	}
+
+	// スキンはプロフィールAPIから取得できる場合のみ使う
+	if c.provider != nil {
+		timeout := profile.DefaultTimeout
+		if config.Players.Remote.Timeout > 0 {
+			timeout = time.Duration(config.Players.Remote.Timeout) * time.Second
+		}
+
+		path := config.Avatar.Path
+		if path == "" {
+			path = DefaultSkinPath
+		}
+		c.skins = skin.NewStore(path, &http.Client{Timeout: timeout})
+	}
+	if c.skinTTL <= 0 {
+		c.skinTTL = DefaultSkinTTL
+	}
+
+	if config.Players.UserCache.IsEnabled() {
+		c.usercache = newUserCache(config.UserCachePath(), config.Players.UserCache.IgnoreExpiry)
+	}
+
+	// 進捗の履歴を記録する
+	// - 開けない場合は履歴なしで動作する
+	if config.History.Enabled {
+		store, err := history.Open(config.History.Path)
+		if err != nil {
+			logger.Warnf("failed to open history store: %v", err)
+		} else {
+			c.history = store
+		}
+	}
+
+	// 進捗フォルダを監視し、変更のあったプレイヤーのみ再構築する
+	// - 監視できない場合は cacheSecond による時間での失効にフォールバック
+	if config.Watch.Enabled {
+		w, err := newWatcher("advancement", c.basePath, time.Duration(config.Watch.Debounce)*time.Millisecond, c.onAdvancementChanged)
+		if err != nil {
+			logger.Warnf("failed to watch advancement directory, fallback to cache expiry: %v", err)
+		} else {
+			c.watcher = w
+		}
+
+		w, err = newWatcher("stats", c.statsPath, time.Duration(config.Watch.Debounce)*time.Millisecond, c.onStatsChanged)
+		if err != nil {
+			logger.Warnf("failed to watch stats directory, fallback to cache expiry: %v", err)
+		} else {
+			c.statsWatcher = w
+		}
+	}
+
+	// ランキング等の全プレイヤー集計はバックグラウンドで行う
+	go c.runAggregate(time.Duration(config.Aggregate.Interval) * time.Second)
+
+	return c
 }
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+func (c collector) Compare(userIds []string) (*responses.CompareResponse, error) {
+	summaries := make([]*model.PlayerAdvancementSummary, 0, len(userIds))
+	for _, id := range userIds {
+		summary, err := c.Load(id)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	keys := make([]string, 0, len(c.ref))
+	for k := range c.ref {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// 進捗ごとに達成したプレイヤーと達成日時を突き合わせ
+	unique := make(map[string][]string)
+	advancements := make([]model.AdvancementComparison, 0, len(keys))
+	for _, k := range keys {
+		row := model.AdvancementComparison{
+			Key:  k,
+			Done: make(map[string]*time.Time),
+		}
+
+		var first *time.Time
+		for i, summary := range summaries {
+			adv, exists := summary.Advancements[k]
+			if !exists || !adv.Done {
+				continue
+			}
+
+			t := adv.CompletedAt()
+			row.Done[userIds[i]] = t
+
+			if t != nil && (first == nil || t.Before(*first)) {
+				first = t
+				row.First = userIds[i]
+			}
+		}
+
+		// 1人だけが達成している進捗
+		if len(row.Done) == 1 {
+			for id := range row.Done {
+				unique[id] = append(unique[id], k)
+				row.First = id
+			}
+		}
+
+		advancements = append(advancements, row)
+	}
+
+	players := make([]model.PlayerComparison, 0, len(summaries))
+	for i, summary := range summaries {
+		u := unique[userIds[i]]
+		if u == nil {
+			u = []string{}
+		}
+
+		players = append(players, model.PlayerComparison{
+			Player:   c.cachedProfile(userIds[i]),
+			Progress: summary.Progress,
+			Unique:   u,
+		})
+	}
+
+	return &responses.CompareResponse{
+		Players:      players,
+		Advancements: advancements,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"sort"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+// Diagnostics は advancementlist.yml に無いcriteriaを、記録しているプレイヤーと共に返す
+func (c collector) Diagnostics() *responses.DiagnosticsResponse {
+	players, updated := c.aggregate.Snapshot()
+
+	unknown := make(map[[2]string]*model.CriterionDiagnostic)
+	for _, p := range players {
+		for k, v := range p.Summary.Advancements {
+			for criterion := range v.ExtraCriteria {
+				id := [2]string{k, criterion}
+				if _, exists := unknown[id]; !exists {
+					unknown[id] = &model.CriterionDiagnostic{
+						Key:       k,
+						Criterion: criterion,
+					}
+				}
+				unknown[id].Players = append(unknown[id].Players, p.Profile.Id)
+			}
+		}
+	}
+
+	criteria := make([]model.CriterionDiagnostic, 0, len(unknown))
+	for _, v := range unknown {
+		criteria = append(criteria, *v)
+	}
+	sort.Slice(criteria, func(i, j int) bool {
+		if criteria[i].Key != criteria[j].Key {
+			return criteria[i].Key < criteria[j].Key
+		}
+		return criteria[i].Criterion < criteria[j].Criterion
+	})
+
+	return &responses.DiagnosticsResponse{
+		UnknownCriteria: criteria,
+		Players:         len(players),
+		Updated:         updated,
+	}
+}
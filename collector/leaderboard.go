@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+const (
+	DefaultLeaderboardLimit = 100
+)
+
+func (c collector) Leaderboard(query model.LeaderboardQuery) *responses.LeaderboardResponse {
+	players, updated := c.aggregate.Snapshot()
+
+	ranking := make([]model.LeaderboardEntry, 0, len(players))
+	for _, p := range players {
+		// 種類の指定がある場合は、該当する進捗のみで集計
+		advancements := p.Summary.Advancements
+		if query.Type != "" {
+			advancements = make(map[string]*model.PlayerAdvancement)
+			for k, v := range p.Summary.Advancements {
+				if v.Type == query.Type {
+					advancements[k] = v
+				}
+			}
+		}
+
+		entry := model.LeaderboardEntry{
+			Player:   p.Profile,
+			Progress: *c.summarize(advancements),
+		}
+		for _, v := range advancements {
+			if t := v.CompletedAt(); t != nil && (entry.Latest == nil || t.After(*entry.Latest)) {
+				entry.Latest = t
+			}
+		}
+
+		ranking = append(ranking, entry)
+	}
+
+	// 達成数 > 進捗率 > 最後の達成が早い順, 同率の場合は名前順
+	sort.SliceStable(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if r := compareRank(a, b); r != 0 {
+			return r < 0
+		}
+
+		if an, bn := strings.ToLower(a.Player.Name), strings.ToLower(b.Player.Name); an != bn {
+			return an < bn
+		}
+		return a.Player.Id < b.Player.Id
+	})
+
+	// 同率は同順位とする (1, 2, 2, 4, ...)
+	for i := range ranking {
+		if i > 0 && compareRank(ranking[i-1], ranking[i]) == 0 {
+			ranking[i].Rank = ranking[i-1].Rank
+			continue
+		}
+		ranking[i].Rank = i + 1
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLeaderboardLimit
+	}
+
+	total := len(ranking)
+	start, end := paginate(total, query.Offset, limit)
+
+	return &responses.LeaderboardResponse{
+		Ranking: ranking[start:end],
+		Total:   total,
+		Limit:   limit,
+		Offset:  query.Offset,
+		Updated: updated,
+	}
+}
+
+func compareRank(a, b model.LeaderboardEntry) int {
+	if a.Progress.Done != b.Progress.Done {
+		if a.Progress.Done > b.Progress.Done {
+			return -1
+		}
+		return 1
+	}
+
+	if a.Progress.Percentage != b.Progress.Percentage {
+		if a.Progress.Percentage > b.Progress.Percentage {
+			return -1
+		}
+		return 1
+	}
+
+	switch {
+	case a.Latest == nil && b.Latest == nil:
+		return 0
+	case a.Latest == nil:
+		return 1
+	case b.Latest == nil:
+		return -1
+	case a.Latest.Before(*b.Latest):
+		return -1
+	case b.Latest.Before(*a.Latest):
+		return 1
+	}
+
+	return 0
+}
+
+// paginate は offset, limit を件数に収まる範囲に丸める
+func paginate(total, offset, limit int) (int, int) {
+	start := offset
+	if start > total {
+		start = total
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return start, end
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/nbt"
+)
+
+var (
+	// playerGameType の値
+	gameModes = map[int64]model.GameMode{
+		0: model.GameModeSurvival,
+		1: model.GameModeCreative,
+		2: model.GameModeAdventure,
+		3: model.GameModeSpectator,
+	}
+
+	// 1.16より前の Dimension (数値)
+	legacyDimensions = map[int64]string{
+		-1: "minecraft:the_nether",
+		0:  "minecraft:overworld",
+		1:  "minecraft:the_end",
+	}
+)
+
+func (c collector) PlayerData(userId string) (*model.PlayerDataProfile, error) {
+	if cache, exists := c.playerdataCache.Get(userId); exists {
+		if time.Now().Before(cache.Updated.Add(time.Duration(c.cacheSecond) * time.Second)) {
+			return &cache.Response, nil
+		}
+	}
+
+	var (
+		filepath = path.Join(c.playerdataPath, userId+".dat")
+	)
+
+	fileinfo, err := os.Stat(filepath)
+	if err != nil {
+		return nil, ErrPlayerDataNotFound
+	}
+
+	_, data, err := nbt.ReadFile(filepath)
+	if err != nil {
+		logger.Warn(err)
+		return nil, ErrParsePlayerData
+	}
+
+	profile := model.PlayerDataProfile{
+		Updated: fileinfo.ModTime().UTC(),
+		Cached:  time.Now().UTC(),
+	}
+	profile.XpLevel, _ = data.Int("XpLevel")
+	profile.XpProgress, _ = data.Float("XpP")
+	profile.XpTotal, _ = data.Int("XpTotal")
+	profile.Health, _ = data.Float("Health")
+	profile.FoodLevel, _ = data.Int("foodLevel")
+	profile.FoodSaturation, _ = data.Float("foodSaturationLevel")
+
+	if v, ok := data.Int("playerGameType"); ok {
+		profile.GameMode = gameModes[v]
+	}
+
+	if v, ok := data.String("Dimension"); ok {
+		profile.Dimension = v
+	} else if v, ok := data.Int("Dimension"); ok {
+		profile.Dimension = legacyDimensions[v]
+	}
+
+	// 座標は設定で許可されている場合のみ返す
+	if pos, ok := data.Floats("Pos"); ok && len(pos) == 3 && c.showPosition {
+		profile.Position = &model.PlayerPosition{
+			X: pos[0],
+			Y: pos[1],
+			Z: pos[2],
+		}
+	}
+
+	c.playerdataCache.Set(userId, profile, profile.Cached)
+
+	return &profile, nil
+}
@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/lang"
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	advancementFileExt = ".json"
+)
+
+// playerIds は進捗フォルダの <uuid>.json からプレイヤーのUUIDを返す
+// - サーバーが残す .json_old 等のバックアップや、UUIDでないファイルは除く
+func (c collector) playerIds() ([]string, error) {
+	files, err := os.ReadDir(c.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var uuids []string
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != advancementFileExt {
+			continue
+		}
+
+		id := strings.TrimSuffix(f.Name(), advancementFileExt)
+		if !uuidPattern.MatchString(id) {
+			continue
+		}
+
+		uuids = append(uuids, strings.ToLower(id))
+	}
+
+	return uuids, nil
+}
+
+// playerListSummary は一覧用に進捗の達成数・最終更新日時・最後に達成した進捗を返す
+// - 読み込めない場合は nil
+func (c collector) playerListSummary(userId string, l *lang.Lang) *model.PlayerListSummary {
+	summary, err := c.Load(userId)
+	if err != nil {
+		logger.Warn(err)
+		return nil
+	}
+
+	resp := &model.PlayerListSummary{
+		Progress:     summary.Progress,
+		LastActivity: summary.Updated,
+	}
+
+	for k, v := range summary.Advancements {
+		t := v.CompletedAt()
+		if t == nil {
+			continue
+		}
+
+		if resp.Latest == nil || t.After(resp.Latest.CompletedAt) || (t.Equal(resp.Latest.CompletedAt) && k < resp.Latest.Key) {
+			resp.Latest = &model.PlayerLatestAdvancement{
+				Key:         k,
+				Title:       v.Display.Title,
+				CompletedAt: *t,
+			}
+		}
+	}
+
+	// キャッシュ上の進捗は既定の言語のため、それ以外の言語は翻訳し直す
+	if resp.Latest != nil && l != c.lang {
+		var display model.PlayerAdvancementDisplay
+		c.display(l, resp.Latest.Key, &display)
+		resp.Latest.Title = display.Title
+	}
+
+	return resp
+}
+
+// sortPlayers は一覧を並び替える
+// - 既定は名前の昇順、進捗・最終更新日時は降順
+// - 同じ値の場合は名前順 (集計できなかったプレイヤーは最後)
+func sortPlayers(players []model.PlayerListEntry, by model.PlayerSort, order model.SortOrder) {
+	if order == "" {
+		order = model.SortAsc
+		if by == model.PlayerSortProgress || by == model.PlayerSortActivity {
+			order = model.SortDesc
+		}
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+
+		if by == model.PlayerSortProgress || by == model.PlayerSortActivity {
+			if (a.Summary == nil) != (b.Summary == nil) {
+				return b.Summary == nil
+			}
+
+			if a.Summary != nil {
+				if r := comparePlayer(a.Summary, b.Summary, by); r != 0 {
+					return (r < 0) == (order == model.SortAsc)
+				}
+			}
+		}
+
+		an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+		if an != bn {
+			// 名前順の場合のみ order に従う
+			if by == model.PlayerSortProgress || by == model.PlayerSortActivity || order == model.SortAsc {
+				return an < bn
+			}
+			return an > bn
+		}
+		return a.Id < b.Id
+	})
+}
+
+func comparePlayer(a, b *model.PlayerListSummary, by model.PlayerSort) int {
+	switch by {
+	case model.PlayerSortProgress:
+		if a.Progress.Done != b.Progress.Done {
+			if a.Progress.Done < b.Progress.Done {
+				return -1
+			}
+			return 1
+		}
+		if a.Progress.Percentage != b.Progress.Percentage {
+			if a.Progress.Percentage < b.Progress.Percentage {
+				return -1
+			}
+			return 1
+		}
+
+	case model.PlayerSortActivity:
+		if a.LastActivity.Before(b.LastActivity) {
+			return -1
+		}
+		if b.LastActivity.Before(a.LastActivity) {
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"math"
+	"sort"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+func (c collector) AdvancementStats() *responses.AdvancementStatsResponse {
+	players, updated := c.aggregate.Snapshot()
+	stats := c.aggregate.Stats()
+
+	advancements := make([]model.AdvancementStats, 0, len(stats))
+	for _, v := range stats {
+		advancements = append(advancements, v)
+	}
+	sort.Slice(advancements, func(i, j int) bool {
+		return advancements[i].Key < advancements[j].Key
+	})
+
+	return &responses.AdvancementStatsResponse{
+		Advancements: advancements,
+		Players:      len(players),
+		Updated:      updated,
+	}
+}
+
+// computeStats は進捗ごとの達成人数・達成率と、最初に達成したプレイヤーを集計する
+func (c collector) computeStats(players []playerSnapshot) map[string]model.AdvancementStats {
+	stats := make(map[string]model.AdvancementStats, len(c.ref))
+	for k := range c.ref {
+		stats[k] = model.AdvancementStats{
+			Key: k,
+		}
+	}
+
+	for _, p := range players {
+		for k, v := range p.Summary.Advancements {
+			s, exists := stats[k]
+			if !exists || !v.Done {
+				continue
+			}
+
+			s.Completed += 1
+			if t := v.CompletedAt(); t != nil && (s.First == nil || t.Before(s.First.Time)) {
+				s.First = &model.AdvancementStatsFirst{
+					Player: p.Profile,
+					Time:   *t,
+				}
+			}
+
+			stats[k] = s
+		}
+	}
+
+	if len(players) > 0 {
+		for k, s := range stats {
+			s.Percentage = math.Floor((float64(s.Completed)/float64(len(players)))*1000) / 1000
+			stats[k] = s
+		}
+	}
+
+	return stats
+}
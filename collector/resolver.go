@@ -0,0 +1,309 @@
+package collector
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/config"
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/profile"
+)
+
+const (
+	DefaultConcurrency = 4
+)
+
+var (
+	uuidPattern         = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	unsignedUuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+	playerNamePattern   = regexp.MustCompile(`^[0-9A-Za-z_]{1,16}$`)
+)
+
+// ResolvePlayerId はUUID, ハイフン無しのUUID, プレイヤー名(大文字小文字を区別しない)からUUIDを返す
+// - 名前は現在の名前 -> 以前の名前 -> プロフィールAPI の順に探す
+func (c collector) ResolvePlayerId(idOrName string) (string, error) {
+	switch {
+	case uuidPattern.MatchString(idOrName):
+		return strings.ToLower(idOrName), nil
+	case unsignedUuidPattern.MatchString(idOrName):
+		return profile.FormatUUID(idOrName), nil
+	}
+
+	current := make(map[string]bool)
+	previous := make(map[string]bool)
+
+	if c.usercache != nil {
+		for _, id := range c.usercache.FindByName(idOrName) {
+			current[id] = true
+		}
+	}
+
+	if c.usePlayerCache {
+		c.playercacheMu.Lock()
+		ids, aliases := c.playercache.FindByName(idOrName)
+		c.playercacheMu.Unlock()
+
+		for _, id := range ids {
+			current[id] = true
+		}
+		for _, id := range aliases {
+			previous[id] = true
+		}
+	}
+
+	for _, candidates := range []map[string]bool{current, previous} {
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			for id := range candidates {
+				return id, nil
+			}
+		default:
+			return "", ErrPlayerNameAmbiguous
+		}
+	}
+
+	// 手元に無い場合は、一括APIで名前からUUIDを引く (Java版の名前として正しい場合のみ)
+	if profile.SupportsBulk(c.provider) && playerNamePattern.MatchString(idOrName) {
+		found, err := c.provider.(profile.BulkResolver).ProfilesByName([]string{idOrName})
+		if err != nil {
+			logger.Warn(err)
+		} else if p, exists := found[strings.ToLower(idOrName)]; exists {
+			return p.Id, nil
+		}
+	}
+
+	return "", ErrPlayerNameNotFound
+}
+
+// resolveProfile はUUIDの形式に応じてプレイヤー名を解決し、アカウントの種類を付与する
+// - オフラインモード・Floodgate のUUIDはプロフィールAPIに存在しないため、手元の情報のみで解決する
+func (c collector) resolveProfile(id string, remote bool) (*model.PlayerProfile, error) {
+	var (
+		p   *model.PlayerProfile
+		err error
+	)
+
+	accountType := profile.AccountTypeOf(id)
+	switch accountType {
+	case model.AccountOffline:
+		p = c.resolveOffline(id)
+	case model.AccountFloodgate:
+		p = c.resolveFloodgate(id)
+	default:
+		p, err = c.resolveOnline(id, remote)
+	}
+
+	if p != nil {
+		p.Type = accountType
+		p.Xuid, _ = profile.FloodgateXUID(id)
+	}
+
+	return p, err
+}
+
+// unresolvedProfile は名前が解決できなかったプレイヤーのIDのみのプロフィールを返す
+func unresolvedProfile(id string) model.PlayerProfile {
+	xuid, _ := profile.FloodgateXUID(id)
+
+	return model.PlayerProfile{
+		Id:   id,
+		Type: profile.AccountTypeOf(id),
+		Xuid: xuid,
+	}
+}
+
+// resolveOnline は usercache.json -> playercache -> プロフィールAPI の順にプレイヤー名を解決する
+// - 有効なものが無くAPIも使えない場合は、期限切れの usercache.json を代わりに返す
+func (c collector) resolveOnline(id string, remote bool) (*model.PlayerProfile, error) {
+	local, fresh := c.resolveLocal(id)
+	if local != nil && fresh {
+		return local, nil
+	}
+
+	if !remote || c.provider == nil {
+		return local, nil
+	}
+
+	// プロフィールAPIでUUIDからProfileを得る (スキンもアバター用にキャッシュする)
+	profile, err := c.fetchProfile(id)
+	if err != nil {
+		return local, err
+	}
+
+	return c.observe(model.PlayerProfile{
+		Id:   id,
+		Name: profile.Name,
+	}, true), nil
+}
+
+// resolveOffline はオフラインモードのUUID (v3) のプレイヤー名を解決する
+// - UUIDは名前から決まるため、期限切れの usercache.json でも名前は正しい
+// - オンラインモードから切り替えたサーバーでは usercache.json に別のUUIDで載っているため、既知の名前から逆引きする
+func (c collector) resolveOffline(id string) *model.PlayerProfile {
+	if p, _ := c.resolveLocal(id); p != nil {
+		return p
+	}
+
+	for _, name := range c.knownNames() {
+		if profile.OfflineUUID(name) == id {
+			return c.observe(model.PlayerProfile{
+				Id:   id,
+				Name: name,
+			}, false)
+		}
+	}
+
+	return nil
+}
+
+// resolveFloodgate は Floodgate のUUIDのプレイヤー名を解決する
+// - usercache.json に無い場合は、設定した XUID とゲーマータグの対応を使う
+func (c collector) resolveFloodgate(id string) *model.PlayerProfile {
+	if p, _ := c.resolveLocal(id); p != nil {
+		return p
+	}
+
+	xuid, ok := profile.FloodgateXUID(id)
+	if !ok {
+		return nil
+	}
+
+	gamertag, exists := c.floodgateXUIDs[xuid]
+	if !exists {
+		return nil
+	}
+
+	return c.observe(model.PlayerProfile{
+		Id:   id,
+		Name: c.floodgatePrefix + gamertag,
+	}, false)
+}
+
+// knownNames は usercache.json と playercache に記録されている全ての名前を返す (以前の名前を含む)
+func (c collector) knownNames() []string {
+	var names []string
+
+	if c.usercache != nil {
+		names = append(names, c.usercache.Names()...)
+	}
+
+	if c.usePlayerCache {
+		c.playercacheMu.Lock()
+		for _, entry := range c.playercache.Players {
+			names = append(names, entry.Name)
+			for _, alias := range entry.Aliases {
+				names = append(names, alias.Name)
+			}
+		}
+		c.playercacheMu.Unlock()
+	}
+
+	return names
+}
+
+// resolveLocal はAPIを使わずにプレイヤー名を解決する
+// - usercache.json は expiresOn まで、playercache は ttl まで有効
+func (c collector) resolveLocal(id string) (*model.PlayerProfile, bool) {
+	var stale *model.PlayerProfile
+
+	if c.usercache != nil {
+		profile, fresh := c.usercache.Lookup(id)
+		if profile != nil && fresh {
+			return c.observe(*profile, false), true
+		}
+		stale = profile
+	}
+
+	if c.usePlayerCache {
+		c.playercacheMu.Lock()
+		entry, exists := c.playercache.Players[id]
+		c.playercacheMu.Unlock()
+
+		if exists {
+			p := entry.Profile()
+			if entry.Fresh(c.playercacheTTL, time.Now()) {
+				return &p, true
+			}
+
+			// 期限切れの場合は、APIで確認済みの playercache を優先して代替にする
+			stale = &p
+		}
+	}
+
+	return stale, false
+}
+
+// verifyStaleNames は期限切れの usercache.json にしか無いプレイヤーを一括APIで確認し、
+// 名前が変わっていなければ playercache に保存する (1人ずつ問い合わせるより少ないリクエストで済む)
+func (c collector) verifyStaleNames(ids []string) {
+	// 一括APIに対応したプロバイダ (mojang) が無い場合は何もしない
+	if !profile.SupportsBulk(c.provider) || !c.usePlayerCache {
+		return
+	}
+	bulk := c.provider.(profile.BulkResolver)
+
+	stale := make(map[string]string)
+	var names []string
+	for _, id := range ids {
+		// 一括APIで確認できるのは正規のアカウントのみ
+		if profile.AccountTypeOf(id) != model.AccountOnline {
+			continue
+		}
+
+		p, fresh := c.resolveLocal(id)
+		if p == nil || fresh {
+			continue
+		}
+
+		stale[strings.ToLower(p.Name)] = id
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	found, err := bulk.ProfilesByName(names)
+	if err != nil {
+		if !errors.Is(err, profile.ErrBulkUnsupported) {
+			logger.Warn(err)
+		}
+		return
+	}
+
+	for name, id := range stale {
+		if p, exists := found[name]; exists && strings.EqualFold(p.Id, id) {
+			c.observe(model.PlayerProfile{
+				Id:   id,
+				Name: p.Name,
+			}, true)
+		}
+	}
+}
+
+// observe は確認したプレイヤー名を playercache に記録し、以前の名前を付与して返す
+//   - confirmed: APIで確認した場合は確認日時を更新する
+//     (usercache.json の場合は、新しいプレイヤー・名前の変更があった場合のみ記録する)
+//   - バックグラウンド集計とリクエストから同時に呼ばれるため排他する
+func (c collector) observe(p model.PlayerProfile, confirmed bool) *model.PlayerProfile {
+	if !c.usePlayerCache {
+		return &p
+	}
+
+	c.playercacheMu.Lock()
+	defer c.playercacheMu.Unlock()
+
+	entry, exists := c.playercache.Players[p.Id]
+	if confirmed || !exists || entry.Name != p.Name {
+		c.playercache.Observe(p, time.Now().UTC(), confirmed)
+		if err := c.playercache.Save(config.PLAYERCACHE_PATH); err != nil {
+			logger.Warn(err)
+		}
+	}
+
+	profile := c.playercache.Players[p.Id].Profile()
+	return &profile
+}
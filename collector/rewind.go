@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+// At はcriteriaの達成日時をもとに、指定日時時点の進捗を再構成する
+func (c collector) At(at time.Time, summary *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary {
+	advancements := make(map[string]*model.PlayerAdvancement, len(summary.Advancements))
+	for k, v := range summary.Advancements {
+		adv := *v
+
+		// 指定日時より後に達成したcriteriaは未達成として扱う
+		adv.Criteria = make(map[string]*time.Time, len(v.Criteria))
+		for criterion, t := range v.Criteria {
+			if t == nil || t.After(at) {
+				adv.Criteria[criterion] = nil
+				continue
+			}
+			adv.Criteria[criterion] = t
+		}
+
+		adv.ExtraCriteria = nil
+		for criterion, t := range v.ExtraCriteria {
+			if t == nil || t.After(at) {
+				continue
+			}
+			if adv.ExtraCriteria == nil {
+				adv.ExtraCriteria = make(map[string]*time.Time)
+			}
+			adv.ExtraCriteria[criterion] = t
+		}
+
+		adv.Done = doneAt(c.ref[k].RequirementGroups(), v, adv.Criteria)
+
+		adv.Progress = c.progress(c.ref[k], adv.Criteria, adv.Done)
+
+		advancements[k] = &adv
+	}
+
+	return &model.PlayerAdvancementSummary{
+		Advancements: advancements,
+		Progress:     *c.summarize(advancements),
+		Tabs:         c.summarizeTabs(advancements),
+		Updated:      summary.Updated,
+		Cached:       summary.Cached,
+	}
+}
+
+// doneAt は巻き戻したcriteriaで、requirements の全てのグループを満たすかを返す
+// - グループのcriteriaに達成日時が1つも無い場合 (日時が不明) は、現在の達成状態に従う
+// - 設定外のcriteria (ExtraCriteria) は判定に含めない
+func doneAt(groups [][]string, current *model.PlayerAdvancement, criteria map[string]*time.Time) bool {
+	if len(groups) == 0 {
+		return current.Done
+	}
+
+	for _, group := range groups {
+		satisfied, timestamped := false, false
+		for _, criterion := range group {
+			if criteria[criterion] != nil {
+				satisfied = true
+				break
+			}
+			if current.Criteria[criterion] != nil {
+				timestamped = true
+			}
+		}
+
+		if !satisfied && (timestamped || !current.Done) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/lang"
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+const (
+	StatCategoryCustom   = "minecraft:custom"
+	StatCategoryMined    = "minecraft:mined"
+	StatCategoryKilled   = "minecraft:killed"
+	StatCategoryKilledBy = "minecraft:killed_by"
+)
+
+func (c collector) LoadStats(userId string) (*model.PlayerStatsSummary, error) {
+	// キャッシュの扱いは進捗と同じ
+	if cache, exists := c.statsCache.Get(userId); exists {
+		if c.statsWatcher.Active() || time.Now().Before(cache.Updated.Add(time.Duration(c.cacheSecond)*time.Second)) {
+			return &cache.Response, nil
+		}
+	}
+
+	return c.buildStats(userId)
+}
+
+func (c collector) buildStats(userId string) (*model.PlayerStatsSummary, error) {
+	var (
+		filepath = path.Join(c.statsPath, userId+".json")
+	)
+
+	fileinfo, err := os.Stat(filepath)
+	if err != nil {
+		return nil, ErrStatsNotFound
+	}
+
+	b, err := os.ReadFile(filepath)
+	if err != nil {
+		logger.Warn(err)
+		return nil, ErrOpenStatsJSON
+	}
+
+	var original model.MinecraftStats
+	if err := json.Unmarshal(b, &original); err != nil {
+		logger.Warn(err)
+		return nil, ErrParseStats
+	}
+
+	// 統計のキーを言語ファイルの名前に変換
+	categories := make(map[string]*model.PlayerStatsCategory, len(original.Stats))
+	for category, values := range original.Stats {
+		stats := make([]model.PlayerStatistic, 0, len(values))
+		for k, v := range values {
+			stats = append(stats, model.PlayerStatistic{
+				Key:   k,
+				Name:  statName(c.lang, category, k),
+				Value: v,
+			})
+		}
+
+		// 値の大きい順
+		sort.Slice(stats, func(i, j int) bool {
+			if stats[i].Value != stats[j].Value {
+				return stats[i].Value > stats[j].Value
+			}
+			return stats[i].Key < stats[j].Key
+		})
+
+		categories[category] = &model.PlayerStatsCategory{
+			Key:   category,
+			Name:  translate(c.lang, langKey("stat_type", category), category),
+			Stats: stats,
+		}
+	}
+
+	now := time.Now().UTC()
+	resp := model.PlayerStatsSummary{
+		Categories: categories,
+		Updated:    fileinfo.ModTime().UTC(),
+		Cached:     now,
+	}
+
+	c.statsCache.Set(userId, resp, now)
+
+	return &resp, nil
+}
+
+// onStatsChanged はファイル監視から呼ばれ、該当プレイヤーの統計のみ再構築する
+func (c collector) onStatsChanged(userId string) {
+	if _, err := c.buildStats(userId); err != nil {
+		logger.Debugf("invalidate stats cache: %s: %v", userId, err)
+		c.statsCache.Delete(userId)
+	}
+}
+
+// StatsResponse は指定されたカテゴリの統計を返す
+// - キャッシュ上の名前は既定の言語のため、それ以外の言語は翻訳し直す
+func (c collector) StatsResponse(language string, categories []string, summary *model.PlayerStatsSummary) *responses.PlayerStatsResponse {
+	resp := make([]*model.PlayerStatsCategory, 0, len(summary.Categories))
+	if len(categories) == 0 {
+		for _, v := range summary.Categories {
+			resp = append(resp, v)
+		}
+	} else {
+		for _, k := range categories {
+			if v, exists := summary.Categories[k]; exists {
+				resp = append(resp, v)
+			}
+		}
+	}
+
+	if l := c.languages.Get(language); l != c.lang {
+		for i, v := range resp {
+			category := &model.PlayerStatsCategory{
+				Key:   v.Key,
+				Name:  translate(l, langKey("stat_type", v.Key), v.Key),
+				Stats: make([]model.PlayerStatistic, len(v.Stats)),
+			}
+			for j, stat := range v.Stats {
+				stat.Name = statName(l, v.Key, stat.Key)
+				category.Stats[j] = stat
+			}
+			resp[i] = category
+		}
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Key < resp[j].Key
+	})
+
+	return &responses.PlayerStatsResponse{
+		Categories: resp,
+		Updated:    summary.Updated,
+		Cached:     summary.Cached,
+	}
+}
+
+// statName は統計のカテゴリに応じて、ブロック・アイテム・エンティティ等の名前を返す
+func statName(l *lang.Lang, category, key string) string {
+	switch category {
+	case StatCategoryCustom:
+		return translate(l, langKey("stat", key), key)
+	case StatCategoryMined:
+		return translate(l, langKey("block", key), key)
+	case StatCategoryKilled, StatCategoryKilledBy:
+		return translate(l, langKey("entity", key), key)
+	}
+
+	// crafted, used, broken, picked_up, dropped はアイテム (ブロックのアイテムはブロック名)
+	if name, exists := l.Translate(langKey("item", key)); exists {
+		return name
+	}
+	return translate(l, langKey("block", key), key)
+}
+
+// translate は言語ファイルから名前を返す (無い場合は fallback)
+func translate(l *lang.Lang, key, fallback string) string {
+	if name, exists := l.Translate(key); exists {
+		return name
+	}
+	return fallback
+}
+
+// langKey は minecraft:stone 形式のキーを block.minecraft.stone 形式に変換する
+func langKey(prefix, key string) string {
+	namespace, id, found := strings.Cut(key, ":")
+	if !found {
+		namespace, id = "minecraft", key
+	}
+
+	return prefix + "." + namespace + "." + strings.ReplaceAll(id, "/", ".")
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"com.oykdn.mc-advancement-collector/config"
+	"com.oykdn.mc-advancement-collector/lang"
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+// displayText は進捗のタイトル・説明を装飾ごとの区間に分けて返す
+// - テキストコンポーネントの指定 (データパック) があればそれを、無ければ languageKey の翻訳を使う
+// - どちらも無い場合、タイトルは進捗のキーにする (空にしない)
+func (c collector) displayText(l *lang.Lang, key string, ref config.AdvancementRecord) ([]model.TextSegment, []model.TextSegment) {
+	title := componentText(l, ref.Title, ref.LanguageKey, lang.LANG_SUFFIX_TITLE)
+	if len(title) == 0 {
+		title = []model.TextSegment{{Text: key}}
+	}
+	description := componentText(l, ref.Description, ref.LanguageKey, lang.LANG_SUFFIX_DESCRIPTION)
+
+	return title, description
+}
+
+func componentText(l *lang.Lang, component *lang.Component, languageKey, suffix string) []model.TextSegment {
+	if component != nil {
+		return l.Render(*component)
+	}
+	if languageKey == "" {
+		return nil
+	}
+
+	return l.Render(lang.Component{
+		Translate: languageKey + suffix,
+	})
+}
+
+// display は指定された言語でタイトル・説明を設定する
+func (c collector) display(l *lang.Lang, key string, display *model.PlayerAdvancementDisplay) {
+	ref, exists := c.ref[key]
+	if !exists {
+		return
+	}
+
+	title, description := c.displayText(l, key, ref)
+
+	display.Title = lang.Plain(title)
+	display.Description = lang.Plain(description)
+	display.Segments = &model.PlayerAdvancementDisplaySegments{
+		Title:       title,
+		Description: description,
+	}
+}
+
+// Text はタイトル・説明を指定された言語・形式にする
+//   - キャッシュ上の進捗は既定の言語のため、それ以外の言語は翻訳し直す
+//   - plain: 装飾を除いた文字列 (既定)
+//   - html: 装飾を span で表した HTML
+//   - segments: 文字列に加え、装飾ごとの区間 (display.segments) を返す
+func (c collector) Text(language string, format model.TextFormat, summary *model.PlayerAdvancementSummary) *model.PlayerAdvancementSummary {
+	l := c.languages.Get(language)
+	if format == model.TextSegments && l == c.lang {
+		return summary
+	}
+
+	// キャッシュ上の進捗は共有されているため、コピーに設定する
+	advancements := make(map[string]*model.PlayerAdvancement, len(summary.Advancements))
+	for k, v := range summary.Advancements {
+		adv := *v
+
+		if l != c.lang {
+			c.display(l, k, &adv.Display)
+		}
+
+		if format == model.TextHTML && adv.Display.Segments != nil {
+			adv.Display.Title = lang.HTML(adv.Display.Segments.Title)
+			adv.Display.Description = lang.HTML(adv.Display.Segments.Description)
+		}
+		if format != model.TextSegments {
+			adv.Display.Segments = nil
+		}
+
+		advancements[k] = &adv
+	}
+
+	resp := *summary
+	resp.Advancements = advancements
+
+	return &resp
+}
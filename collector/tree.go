@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"sort"
+
+	"com.oykdn.mc-advancement-collector/model"
+	"com.oykdn.mc-advancement-collector/model/responses"
+)
+
+// Tree は親進捗をもとに、タブのルート進捗を起点とした木構造で返す
+func (c collector) Tree(summary *model.PlayerAdvancementSummary) *responses.PlayerAdvancementTreeResponse {
+	flat := c.Response(summary)
+
+	nodes := make(map[string]*model.PlayerAdvancementNode, len(flat.Advancements))
+	for _, v := range flat.Advancements {
+		nodes[v.Key] = &model.PlayerAdvancementNode{
+			PlayerAdvancement: v,
+			Children:          []*model.PlayerAdvancementNode{},
+		}
+	}
+
+	// 親が絞り込みで除外されている場合は、さらに上の祖先に繋げる
+	// - 親子関係の循環は設定読み込み時に検証済み
+	roots := []*model.PlayerAdvancementNode{}
+	for _, node := range nodes {
+		parent := c.ref[node.Key].Parent
+		for parent != "" {
+			if _, exists := nodes[parent]; exists {
+				break
+			}
+			parent = c.ref[parent].Parent
+		}
+
+		if parent == "" {
+			roots = append(roots, node)
+			continue
+		}
+
+		nodes[parent].Children = append(nodes[parent].Children, node)
+	}
+
+	for _, root := range roots {
+		c.aggregateNode(root)
+	}
+	sortNodes(roots)
+
+	return &responses.PlayerAdvancementTreeResponse{
+		Tree:     roots,
+		Progress: flat.Progress,
+		Tabs:     flat.Tabs,
+		Updated:  flat.Updated,
+		Cached:   flat.Cached,
+	}
+}
+
+// aggregateNode は子孫を含めた進捗を集計し、部分木に含まれる進捗を返す
+func (c collector) aggregateNode(node *model.PlayerAdvancementNode) map[string]*model.PlayerAdvancement {
+	subtree := map[string]*model.PlayerAdvancement{
+		node.Key: node.PlayerAdvancement,
+	}
+
+	for _, child := range node.Children {
+		for k, v := range c.aggregateNode(child) {
+			subtree[k] = v
+		}
+	}
+	sortNodes(node.Children)
+
+	node.Subtree = *c.summarize(subtree)
+
+	return subtree
+}
+
+func sortNodes(nodes []*model.PlayerAdvancementNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Key < nodes[j].Key
+	})
+}
@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	UserCacheTimeLayout = "2006-01-02 15:04:05 -0700"
+)
+
+type userCacheEntry struct {
+	Name      string `json:"name"`
+	Uuid      string `json:"uuid"`
+	ExpiresOn string `json:"expiresOn"`
+}
+
+// userCache はサーバーの usercache.json からプレイヤー名を引く
+// - ファイルが更新された場合のみ読み直す
+type userCache struct {
+	path         string
+	ignoreExpiry bool
+
+	mu      sync.Mutex
+	modTime time.Time
+	entries map[string]userCacheEntry
+}
+
+func newUserCache(path string, ignoreExpiry bool) *userCache {
+	return &userCache{
+		path:         path,
+		ignoreExpiry: ignoreExpiry,
+		entries:      make(map[string]userCacheEntry),
+	}
+}
+
+// Lookup はUUIDからプロフィールを返す
+// - expiresOn を過ぎている場合は fresh=false (他の解決元が使えない場合の代替として使う)
+func (uc *userCache) Lookup(id string) (profile *model.PlayerProfile, fresh bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if err := uc.reload(); err != nil {
+		logger.Debug(err)
+	}
+
+	entry, exists := uc.entries[strings.ToLower(id)]
+	if !exists {
+		return nil, false
+	}
+
+	fresh = true
+	if !uc.ignoreExpiry {
+		expires, err := time.Parse(UserCacheTimeLayout, entry.ExpiresOn)
+		fresh = err == nil && time.Now().Before(expires)
+	}
+
+	return &model.PlayerProfile{
+		Id:   id,
+		Name: entry.Name,
+	}, fresh
+}
+
+func (uc *userCache) reload() error {
+	fileinfo, err := os.Stat(uc.path)
+	if err != nil {
+		return err
+	}
+	if fileinfo.ModTime().Equal(uc.modTime) {
+		return nil
+	}
+
+	b, err := os.ReadFile(uc.path)
+	if err != nil {
+		return err
+	}
+
+	var list []userCacheEntry
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+
+	entries := make(map[string]userCacheEntry, len(list))
+	for _, e := range list {
+		entries[strings.ToLower(e.Uuid)] = e
+	}
+
+	uc.entries = entries
+	uc.modTime = fileinfo.ModTime()
+
+	return nil
+}
+
+// FindByName は名前が一致するプレイヤーのUUIDを返す (大文字小文字は区別しない)
+func (uc *userCache) FindByName(name string) []string {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if err := uc.reload(); err != nil {
+		logger.Debug(err)
+	}
+
+	var ids []string
+	for id, entry := range uc.entries {
+		if strings.EqualFold(entry.Name, name) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Names は記録されている全ての名前を返す
+func (uc *userCache) Names() []string {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if err := uc.reload(); err != nil {
+		logger.Debug(err)
+	}
+
+	names := make([]string, 0, len(uc.entries))
+	for _, entry := range uc.entries {
+		names = append(names, entry.Name)
+	}
+
+	return names
+}
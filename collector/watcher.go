@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	DefaultWatchDebounce = 500 * time.Millisecond
+)
+
+// watcher は <uuid>.json の変更を監視し、プレイヤー単位で通知する
+// Minecraftは保存時に複数回に分けて書き込むため、debounce の間変更が無くなってから通知する
+type watcher struct {
+	// ログに出す監視対象の名前 (advancement, stats)
+	name     string
+	fsw      *fsnotify.Watcher
+	debounce time.Duration
+	notify   func(string)
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+
+	active atomic.Bool
+}
+
+func newWatcher(name, dir string, debounce time.Duration, notify func(string)) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	w := &watcher{
+		name:     name,
+		fsw:      fsw,
+		debounce: debounce,
+		notify:   notify,
+		timers:   make(map[string]*time.Timer),
+	}
+	w.active.Store(true)
+
+	go w.run()
+
+	return w, nil
+}
+
+// Active は監視が有効かどうかを返す (nilの場合は監視していない)
+func (w *watcher) Active() bool {
+	return w != nil && w.active.Load()
+}
+
+func (w *watcher) run() {
+	defer w.active.Store(false)
+
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+
+			// <uuid>.json 以外 (一時ファイル, バックアップ等) は無視
+			_, basename := filepath.Split(ev.Name)
+			if filepath.Ext(basename) != ".json" {
+				continue
+			}
+
+			w.schedule(strings.TrimSuffix(basename, ".json"))
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+
+			// イベント取りこぼし等が起きた場合は時間での失効にフォールバック
+			logger.Warnf("%s watcher stopped, fallback to cache expiry: %v", w.name, err)
+			w.fsw.Close()
+			return
+		}
+	}
+}
+
+func (w *watcher) schedule(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t, exists := w.timers[id]; exists {
+		t.Reset(w.debounce)
+		return
+	}
+
+	w.timers[id] = time.AfterFunc(w.debounce, func() {
+		w.mu.Lock()
+		delete(w.timers, id)
+		w.mu.Unlock()
+
+		w.notify(id)
+	})
+}
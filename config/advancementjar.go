@@ -0,0 +1,241 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/lang"
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	// ゲーム本体のデータパック名
+	SourceVanilla = "vanilla"
+)
+
+var (
+	ErrAdvancementNotFoundInJar = fmt.Errorf("no advancements found in jar")
+)
+
+// advancementJSON はゲームの進捗定義 (data/<namespace>/advancements/**.json)
+type advancementJSON struct {
+	Parent       string                     `json:"parent"`
+	Display      *advancementJSONDisplay    `json:"display"`
+	Criteria     map[string]json.RawMessage `json:"criteria"`
+	Requirements [][]string                 `json:"requirements"`
+}
+
+type advancementJSONDisplay struct {
+	Title       *lang.Component       `json:"title"`
+	Description *lang.Component       `json:"description"`
+	Frame       model.AdvancementType `json:"frame"`
+	Hidden      bool                  `json:"hidden"`
+}
+
+// ReadAdvancementJar はサーバーのjar (vanilla / Paper) から進捗の定義を読み込む
+// - 1.18 以降の jar は本体の jar を META-INF/versions/ 以下に内包しているため、その中も探す
+// - display の無い進捗 (レシピの解除等) は含めない
+func ReadAdvancementJar(filename string) (*AdvancementList, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	advancements, err := readJar(&r.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(advancements) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAdvancementNotFoundInJar, filename)
+	}
+
+	return &AdvancementList{
+		Advancements: advancements,
+	}, nil
+}
+
+func readJar(r *zip.Reader) (map[string]AdvancementRecord, error) {
+	advancements, _, err := ReadAdvancements(r, SourceVanilla)
+	if err != nil {
+		return nil, err
+	}
+	if len(advancements) > 0 {
+		return advancements, nil
+	}
+
+	// 内包されている jar を探す
+	for _, f := range r.File {
+		if !strings.HasPrefix(f.Name, "META-INF/versions/") || path.Ext(f.Name) != ".jar" {
+			continue
+		}
+
+		b, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		inner, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, err
+		}
+
+		advancements, err := readJar(inner)
+		if err != nil {
+			return nil, err
+		}
+		if len(advancements) > 0 {
+			return advancements, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// ReadAdvancements は data/<namespace>/advancements/ 以下の進捗定義を読み込む
+// - 1.21 以降のフォルダ名 (advancement) にも対応する
+// - source は読み込み元のデータパック名
+// - display の無い進捗は含めず、キーを hidden として返す (データパックが同じキーで上書きして進捗を隠す場合に使う)
+func ReadAdvancements(fsys fs.FS, source string) (advancements map[string]AdvancementRecord, hidden []string, err error) {
+	advancements = make(map[string]AdvancementRecord)
+
+	namespaces, err := fs.ReadDir(fsys, "data")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return advancements, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	for _, ns := range namespaces {
+		if !ns.IsDir() {
+			continue
+		}
+
+		for _, dir := range []string{"advancements", "advancement"} {
+			root := path.Join("data", ns.Name(), dir)
+
+			err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					if errors.Is(err, fs.ErrNotExist) && p == root {
+						return fs.SkipDir
+					}
+					return err
+				}
+				if d.IsDir() || path.Ext(p) != ".json" {
+					return nil
+				}
+
+				b, err := fs.ReadFile(fsys, p)
+				if err != nil {
+					return err
+				}
+
+				key := ns.Name() + ":" + strings.TrimSuffix(strings.TrimPrefix(p, root+"/"), ".json")
+
+				record, ok, err := parseAdvancement(b)
+				if err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+				if !ok {
+					hidden = append(hidden, key)
+					return nil
+				}
+
+				record.Namespace = ns.Name()
+				record.Source = source
+				advancements[key] = record
+
+				return nil
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return advancements, hidden, nil
+}
+
+// parseAdvancement は進捗定義を AdvancementRecord に変換する (display が無い場合は ok=false)
+func parseAdvancement(b []byte) (AdvancementRecord, bool, error) {
+	var adv advancementJSON
+	if err := json.Unmarshal(b, &adv); err != nil {
+		return AdvancementRecord{}, false, err
+	}
+
+	if adv.Display == nil {
+		return AdvancementRecord{}, false, nil
+	}
+
+	criteria := make([]string, 0, len(adv.Criteria))
+	for k := range adv.Criteria {
+		criteria = append(criteria, k)
+	}
+	sort.Strings(criteria)
+
+	// requirements の指定が無い場合は、ゲームと同じく全てのcriteriaが条件
+	metrics := model.MetricsAllOf
+	if len(adv.Requirements) > 0 {
+		metrics = model.MetricsRequirements
+	}
+
+	frame := adv.Display.Frame
+	if frame == "" {
+		frame = model.Task
+	}
+
+	record := AdvancementRecord{
+		Criteria:     criteria,
+		Requirements: adv.Requirements,
+		Parent:       adv.Parent,
+		Title:        adv.Display.Title,
+		Description:  adv.Display.Description,
+		Metrics:      metrics,
+		Hidden:       adv.Display.Hidden,
+		Type:         frame,
+	}
+
+	// ゲーム本体と同じ形式の翻訳キーは languageKey にまとめる
+	if record.Title != nil {
+		if key, ok := record.Title.TranslateKey(); ok && strings.HasSuffix(key, lang.LANG_SUFFIX_TITLE) {
+			record.LanguageKey = strings.TrimSuffix(key, lang.LANG_SUFFIX_TITLE)
+			record.Title = nil
+		}
+	}
+	if record.Description == nil {
+		record.Description = &lang.Component{}
+	} else if key, ok := record.Description.TranslateKey(); ok && key == record.LanguageKey+lang.LANG_SUFFIX_DESCRIPTION {
+		record.Description = nil
+	}
+
+	return record, true, nil
+}
+
+// MergeIcons は手動で設定したアイコンを引き継ぐ (jar の定義にはアイコンの画像が無いため)
+func (l *AdvancementList) MergeIcons(other *AdvancementList) {
+	for k, v := range l.Advancements {
+		if o, exists := other.Advancements[k]; exists && o.Icon != (AdvancementRecordIcon{}) {
+			v.Icon = o.Icon
+			l.Advancements[k] = v
+		}
+	}
+}
@@ -1,26 +1,41 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 
+	"com.oykdn.mc-advancement-collector/lang"
 	"com.oykdn.mc-advancement-collector/model"
 	"gopkg.in/yaml.v2"
 )
 
+var (
+	ErrAdvancementParentNotFound = fmt.Errorf("advancement parent not found")
+	ErrAdvancementParentCycle    = fmt.Errorf("advancement parent cycle detected")
+)
+
 type AdvancementRecord struct {
-	Criteria    []string              `yaml:"criteria"`
-	Parent      string                `yaml:"parent"`
-	LanguageKey string                `yaml:"languageKey"`
-	Metrics     model.MetricsType     `yaml:"metrics"`
-	Hidden      bool                  `yaml:"hidden"`
-	Type        model.AdvancementType `yaml:"type"`
-	Icon        AdvancementRecordIcon `yaml:"icon"`
+	Criteria     []string              `yaml:"criteria"`
+	Requirements [][]string            `yaml:"requirements,omitempty"`
+	Parent       string                `yaml:"parent,omitempty"`
+	LanguageKey  string                `yaml:"languageKey"`
+	Title        *lang.Component       `yaml:"title,omitempty"`
+	Description  *lang.Component       `yaml:"description,omitempty"`
+	Metrics      model.MetricsType     `yaml:"metrics"`
+	Hidden       bool                  `yaml:"hidden"`
+	Type         model.AdvancementType `yaml:"type"`
+	Tab          string                `yaml:"tab,omitempty"`
+	Icon         AdvancementRecordIcon `yaml:"icon,omitempty"`
+	Namespace    string                `yaml:"namespace,omitempty"`
+	Source       string                `yaml:"source,omitempty"`
 }
 
 type AdvancementRecordIcon struct {
-	Url       string `yaml:"url"`
-	InvSprite bool   `yaml:"invsprite"`
-	Pos       int    `yaml:"pos"`
+	Url       string `yaml:"url,omitempty"`
+	InvSprite bool   `yaml:"invsprite,omitempty"`
+	Pos       int    `yaml:"pos,omitempty"`
 }
 
 type AdvancementList struct {
@@ -38,5 +53,155 @@ func LoadAdvancementList(path string) (*AdvancementList, error) {
 		return nil, err
 	}
 
+	list.normalize()
+
+	if err := list.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &list, err
 }
+
+// normalize は省略された設定を補う
+// - タブ・名前空間の指定が無い場合はキーから決める
+// - requirements の指定がある場合は、含まれるcriteriaも対象にする
+func (l *AdvancementList) normalize() {
+	for k, v := range l.Advancements {
+		if v.Tab == "" {
+			v.Tab = DeriveTab(k)
+		}
+		if v.Namespace == "" {
+			v.Namespace = DeriveNamespace(k)
+		}
+		if len(v.Requirements) > 0 {
+			v.Criteria = mergeCriteria(v.Criteria, v.Requirements)
+			if v.Metrics == "" {
+				v.Metrics = model.MetricsRequirements
+			}
+		}
+
+		l.Advancements[k] = v
+	}
+}
+
+// RequirementGroups は達成条件を「criteriaのグループ(OR)の組(AND)」として返す
+// - anyof: 全criteriaで1グループ
+// - allof: criteriaごとに1グループ (ゲームの既定と同じ)
+func (r AdvancementRecord) RequirementGroups() [][]string {
+	if len(r.Requirements) > 0 {
+		return r.Requirements
+	}
+
+	if r.Metrics == model.MetricsAnyOf {
+		if len(r.Criteria) == 0 {
+			return [][]string{}
+		}
+		return [][]string{r.Criteria}
+	}
+
+	groups := make([][]string, 0, len(r.Criteria))
+	for _, k := range r.Criteria {
+		groups = append(groups, []string{k})
+	}
+	return groups
+}
+
+func mergeCriteria(criteria []string, requirements [][]string) []string {
+	exists := make(map[string]bool)
+	for _, k := range criteria {
+		exists[k] = true
+	}
+
+	for _, group := range requirements {
+		for _, k := range group {
+			if !exists[k] {
+				exists[k] = true
+				criteria = append(criteria, k)
+			}
+		}
+	}
+
+	return criteria
+}
+
+// dropOrphans は親が一覧に無い進捗を除き、除いたキーを返す
+// - データパックの display の無い親 (隠しのルート等) は読み込まないため、その子はゲームでも一覧に表示されない
+// - 除いた進捗を親とする進捗も続けて除く
+func (l *AdvancementList) dropOrphans() []string {
+	var dropped []string
+	for {
+		var orphans []string
+		for k, v := range l.Advancements {
+			if v.Parent == "" {
+				continue
+			}
+			if _, exists := l.Advancements[v.Parent]; !exists {
+				orphans = append(orphans, k)
+			}
+		}
+		if len(orphans) == 0 {
+			break
+		}
+
+		for _, k := range orphans {
+			delete(l.Advancements, k)
+		}
+		dropped = append(dropped, orphans...)
+	}
+	sort.Strings(dropped)
+
+	return dropped
+}
+
+// Validate は親進捗が存在すること、親子関係が循環していないことを確認する
+func (l AdvancementList) Validate() error {
+	for k, v := range l.Advancements {
+		if v.Parent == "" {
+			continue
+		}
+
+		if _, exists := l.Advancements[v.Parent]; !exists {
+			return fmt.Errorf("%w: %s -> %s", ErrAdvancementParentNotFound, k, v.Parent)
+		}
+	}
+
+	// 親を辿ってルートに到達しなければ循環している
+	for k := range l.Advancements {
+		visited := map[string]bool{k: true}
+		for p := l.Advancements[k].Parent; p != ""; p = l.Advancements[p].Parent {
+			if visited[p] {
+				return fmt.Errorf("%w: %s", ErrAdvancementParentCycle, k)
+			}
+			visited[p] = true
+		}
+	}
+
+	return nil
+}
+
+// DeriveNamespace は進捗のキーから名前空間を返す (省略時は minecraft)
+func DeriveNamespace(key string) string {
+	namespace, _, found := strings.Cut(key, ":")
+	if !found {
+		return "minecraft"
+	}
+
+	return namespace
+}
+
+// DeriveTab は進捗のキーからタブを決める
+// - minecraft:story/root -> story
+// - <namespace>:<tab>/... -> <namespace>:<tab> (データパック)
+func DeriveTab(key string) string {
+	namespace, path, found := strings.Cut(key, ":")
+	if !found {
+		namespace, path = "minecraft", key
+	}
+
+	tab, _, _ := strings.Cut(path, "/")
+	if namespace == "minecraft" {
+		return tab
+	}
+
+	return namespace + ":" + tab
+}
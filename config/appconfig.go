@@ -2,15 +2,104 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v2"
 )
 
 type AppConfig struct {
-	AdvancementPath string         `yaml:"advancementPath"`
-	Language        string         `yaml:"language"`
-	Cache           int            `yaml:"cache"`
-	Assets          AppConfigAsset `yaml:"assets"`
+	AdvancementPath string             `yaml:"advancementPath"`
+	AdvancementJar  string             `yaml:"advancementJar"`
+	StatsPath       string             `yaml:"statsPath"`
+	PlayerDataPath  string             `yaml:"playerdataPath"`
+	Language        string             `yaml:"language"`
+	Languages       AppConfigLanguages `yaml:"languages"`
+	Cache           int                `yaml:"cache"`
+	Watch           AppConfigWatch     `yaml:"watch"`
+	History         AppConfigHistory   `yaml:"history"`
+	Aggregate       AppConfigAggregate `yaml:"aggregate"`
+	Datapacks       AppConfigDatapacks `yaml:"datapacks"`
+	Profile         AppConfigProfile   `yaml:"profile"`
+	Players         AppConfigPlayers   `yaml:"players"`
+	Avatar          AppConfigAvatar    `yaml:"avatar"`
+	Assets          AppConfigAsset     `yaml:"assets"`
+}
+
+// AppConfigLanguages はリクエストごとに選べる言語の設定 (?lang= または Accept-Language)
+type AppConfigLanguages struct {
+	Available []string  `yaml:"available"`
+	Fallback  *[]string `yaml:"fallback"`
+}
+
+type AppConfigWatch struct {
+	Enabled  bool `yaml:"enabled"`
+	Debounce int  `yaml:"debounce"`
+}
+
+type AppConfigHistory struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type AppConfigAggregate struct {
+	Interval int `yaml:"interval"`
+}
+
+type AppConfigDatapacks struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type AppConfigProfile struct {
+	ShowPosition bool `yaml:"showPosition"`
+}
+
+// AppConfigPlayers はプレイヤー名の解決元の設定
+// - usercache.json -> playercache -> プロフィールAPI の順に解決する
+type AppConfigPlayers struct {
+	UserCache   AppConfigPlayersUserCache   `yaml:"usercache"`
+	PlayerCache AppConfigPlayersPlayerCache `yaml:"playercache"`
+	Remote      AppConfigPlayersRemote      `yaml:"remote"`
+	Floodgate   AppConfigPlayersFloodgate   `yaml:"floodgate"`
+}
+
+type AppConfigPlayersUserCache struct {
+	Enabled      *bool  `yaml:"enabled"`
+	Path         string `yaml:"path"`
+	IgnoreExpiry bool   `yaml:"ignoreExpiry"`
+}
+
+type AppConfigPlayersPlayerCache struct {
+	Enabled *bool `yaml:"enabled"`
+	TTL     int   `yaml:"ttl"`
+}
+
+type AppConfigPlayersRemote struct {
+	Enabled          *bool                      `yaml:"enabled"`
+	Timeout          int                        `yaml:"timeout"`
+	RateLimit        float64                    `yaml:"rateLimit"`
+	Burst            int                        `yaml:"burst"`
+	Retries          *int                       `yaml:"retries"`
+	Concurrency      int                        `yaml:"concurrency"`
+	NegativeCacheTTL int                        `yaml:"negativeCacheTTL"`
+	Providers        []AppConfigProfileProvider `yaml:"providers"`
+}
+
+// AppConfigPlayersFloodgate は Geyser/Floodgate で参加したBedrock版のプレイヤーの設定
+type AppConfigPlayersFloodgate struct {
+	Prefix *string           `yaml:"prefix"`
+	XUIDs  map[string]string `yaml:"xuids"`
+}
+
+type AppConfigProfileProvider struct {
+	Type string `yaml:"type"`
+	Url  string `yaml:"url"`
+	Path string `yaml:"path"`
+}
+
+type AppConfigAvatar struct {
+	Path string `yaml:"path"`
+	TTL  int    `yaml:"ttl"`
 }
 
 type AppConfigAsset struct {
@@ -22,6 +111,55 @@ type AppConfigAssetBackground struct {
 	Completed  string `yaml:"completed"`
 }
 
+// StatsDir は統計情報のフォルダを返す
+// - 指定が無い場合は進捗フォルダと同じワールドの stats/
+func (c AppConfig) StatsDir() string {
+	if c.StatsPath != "" {
+		return c.StatsPath
+	}
+
+	return c.worldDir("stats")
+}
+
+// PlayerDataDir はプレイヤーデータ(<uuid>.dat)のフォルダを返す
+// - 指定が無い場合は進捗フォルダと同じワールドの playerdata/
+func (c AppConfig) PlayerDataDir() string {
+	if c.PlayerDataPath != "" {
+		return c.PlayerDataPath
+	}
+
+	return c.worldDir("playerdata")
+}
+
+// DatapackDir はデータパックのフォルダを返す
+// - 指定が無い場合は進捗フォルダと同じワールドの datapacks/
+func (c AppConfig) DatapackDir() string {
+	if c.Datapacks.Path != "" {
+		return c.Datapacks.Path
+	}
+
+	return c.worldDir("datapacks")
+}
+
+// LevelDatPath はワールドの level.dat のパスを返す
+func (c AppConfig) LevelDatPath() string {
+	return c.worldDir("level.dat")
+}
+
+// UserCachePath はサーバーの usercache.json のパスを返す
+// - 指定が無い場合はワールドフォルダと同じ階層 (サーバーのルート)
+func (c AppConfig) UserCachePath() string {
+	if c.Players.UserCache.Path != "" {
+		return c.Players.UserCache.Path
+	}
+
+	return filepath.Join(filepath.Dir(c.worldDir("")), "usercache.json")
+}
+
+func (c AppConfig) worldDir(name string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(c.AdvancementPath)), name)
+}
+
 func LoadAppConfig(path string) (*AppConfig, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -35,3 +173,32 @@ func LoadAppConfig(path string) (*AppConfig, error) {
 
 	return &conf, err
 }
+
+// IsEnabled は enabled の指定が無い場合は有効として扱う
+func (c AppConfigPlayersUserCache) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+func (c AppConfigPlayersPlayerCache) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+func (c AppConfigPlayersRemote) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// UsernamePrefix は指定が無い場合は Floodgate の既定値 "." を返す
+func (c AppConfigPlayersFloodgate) UsernamePrefix() string {
+	if c.Prefix == nil {
+		return "."
+	}
+	return *c.Prefix
+}
+
+// FallbackLanguages は指定が無い場合は en_us を返す
+func (c AppConfigLanguages) FallbackLanguages() []string {
+	if c.Fallback == nil {
+		return []string{"en_us"}
+	}
+	return *c.Fallback
+}
@@ -1,11 +1,21 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	_logger "com.oykdn.mc-advancement-collector/logger"
+)
+
 const (
 	CONFIG_PATH          = "./config/config.yml"
 	ADVANCEMENTLIST_PATH = "./config/advancementlist.yml"
 	PLAYERCACHE_PATH     = "./config/playercache.yml"
 )
 
+var logger *_logger.ZapLogger = _logger.NewZapLogger()
+
 type Config struct {
 	AppConfig       *AppConfig
 	AdvancementList *AdvancementList
@@ -18,7 +28,7 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
-	advancementlist, err := LoadAdvancementList(ADVANCEMENTLIST_PATH)
+	advancementlist, err := loadAdvancementList(conf)
 	if err != nil {
 		return nil, err
 	}
@@ -34,3 +44,81 @@ func LoadConfig() (*Config, error) {
 		PlayerCache:     playercache,
 	}, nil
 }
+
+// loadAdvancementList は進捗の一覧を読み込む
+//   - advancementJar: サーバーのjarから作り、advancementlist.yml はアイコンの設定のみ使う
+//   - datapacks: ワールドの有効なデータパックの進捗を追加する
+//     (jar を使わない場合は advancementlist.yml の設定を優先する)
+func loadAdvancementList(conf *AppConfig) (*AdvancementList, error) {
+	if conf.AdvancementJar == "" && !conf.Datapacks.Enabled {
+		return LoadAdvancementList(ADVANCEMENTLIST_PATH)
+	}
+
+	manual, err := LoadAdvancementList(ADVANCEMENTLIST_PATH)
+	if err != nil {
+		if conf.AdvancementJar == "" || !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		manual = &AdvancementList{}
+	}
+
+	list := &AdvancementList{
+		Advancements: make(map[string]AdvancementRecord),
+	}
+
+	if conf.AdvancementJar != "" {
+		vanilla, err := ReadAdvancementJar(conf.AdvancementJar)
+		if err != nil {
+			return nil, err
+		}
+		list.Merge(vanilla.Advancements)
+	}
+
+	// 後から読み込んだデータパックが優先される (ゲームと同じ)
+	// - display の無い定義で上書きされた進捗は、ゲームで表示されないため最後に除く
+	hidden := make(map[string]bool)
+	if conf.Datapacks.Enabled {
+		packs, err := DiscoverDatapacks(conf.DatapackDir(), conf.LevelDatPath())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pack := range packs {
+			advancements, removed, err := ReadDatapack(pack)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pack.Id, err)
+			}
+			list.Merge(advancements)
+
+			for k := range advancements {
+				delete(hidden, k)
+			}
+			for _, k := range removed {
+				hidden[k] = true
+			}
+		}
+	}
+
+	if conf.AdvancementJar == "" {
+		list.Merge(manual.Advancements)
+	} else {
+		list.MergeIcons(manual)
+	}
+
+	for k := range hidden {
+		delete(list.Advancements, k)
+	}
+
+	list.normalize()
+
+	// 親の無い進捗があっても、1つのデータパックのために起動できなくならないよう除いて続ける
+	for _, k := range list.dropOrphans() {
+		logger.Warnf("skip advancement whose parent is not found: %s", k)
+	}
+
+	if err := list.Validate(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
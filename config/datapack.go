@@ -0,0 +1,148 @@
+package config
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/nbt"
+)
+
+const (
+	datapackPrefix   = "file/"
+	datapackMetadata = "pack.mcmeta"
+)
+
+// Datapack はワールドの datapacks/ 以下のデータパック (フォルダ または zip)
+type Datapack struct {
+	// level.dat と同じ file/<名前> 形式
+	Id   string
+	Path string
+}
+
+// DiscoverDatapacks は有効なデータパックを読み込み順に返す
+// - level.dat の DataPacks.Enabled がある場合はその順序で、含まれるもののみ
+// - DataPacks.Enabled が無い場合 (level.dat が無い場合を含む) は名前順に全て (DataPacks.Disabled に含まれるものは除く)
+// - level.dat が壊れている等で読めない場合はエラー
+func DiscoverDatapacks(dir, levelDat string) ([]Datapack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	found := make(map[string]Datapack)
+	var ids []string
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(p, datapackMetadata)); err != nil {
+				continue
+			}
+		} else if filepath.Ext(e.Name()) != ".zip" {
+			continue
+		}
+
+		id := datapackPrefix + e.Name()
+		found[id] = Datapack{
+			Id:   id,
+			Path: p,
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// level.dat が無い場合のみ全てのデータパックにフォールバックする
+	// (読めない場合に無効なデータパックまで読み込まないよう、それ以外はエラー)
+	enabled, disabled, err := readDatapackSettings(levelDat)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", levelDat, err)
+		}
+		enabled = nil
+	}
+
+	var packs []Datapack
+	if enabled != nil {
+		for _, id := range enabled {
+			if pack, exists := found[id]; exists {
+				packs = append(packs, pack)
+			}
+		}
+		return packs, nil
+	}
+
+	for _, id := range ids {
+		if !disabled[id] {
+			packs = append(packs, found[id])
+		}
+	}
+
+	return packs, nil
+}
+
+// readDatapackSettings は level.dat の Data.DataPacks を読む
+func readDatapackSettings(levelDat string) ([]string, map[string]bool, error) {
+	_, root, err := nbt.ReadFile(levelDat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, _ := root.Compound("Data")
+	datapacks, _ := data.Compound("DataPacks")
+
+	var enabled []string
+	if list, ok := datapacks.List("Enabled"); ok {
+		enabled = make([]string, 0, len(list))
+		for _, v := range list {
+			if id, ok := v.(string); ok {
+				enabled = append(enabled, id)
+			}
+		}
+	}
+
+	disabled := make(map[string]bool)
+	if list, ok := datapacks.List("Disabled"); ok {
+		for _, v := range list {
+			if id, ok := v.(string); ok {
+				disabled[id] = true
+			}
+		}
+	}
+
+	return enabled, disabled, nil
+}
+
+// ReadDatapack はデータパックの進捗定義を読み込む (hidden は display の無い進捗のキー)
+func ReadDatapack(pack Datapack) (map[string]AdvancementRecord, []string, error) {
+	if strings.HasSuffix(pack.Path, ".zip") {
+		r, err := zip.OpenReader(pack.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer r.Close()
+
+		return ReadAdvancements(r, pack.Id)
+	}
+
+	return ReadAdvancements(os.DirFS(pack.Path), pack.Id)
+}
+
+// Merge は他の一覧の進捗を追加する (同じキーは上書き, データパックによる変更と同じ)
+func (l *AdvancementList) Merge(other map[string]AdvancementRecord) {
+	if l.Advancements == nil {
+		l.Advancements = make(map[string]AdvancementRecord, len(other))
+	}
+
+	for k, v := range other {
+		l.Advancements[k] = v
+	}
+}
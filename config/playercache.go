@@ -2,20 +2,33 @@ package config
 
 import (
 	"os"
+	"strings"
+	"time"
 
 	"com.oykdn.mc-advancement-collector/model"
 	"gopkg.in/yaml.v2"
 )
 
 type PlayerCache struct {
-	Players map[string]model.PlayerProfile `json:"players"`
+	Players map[string]PlayerCacheEntry `json:"players"`
+}
+
+// PlayerCacheEntry はプレイヤー名と、以前の名前の履歴を保持する
+// - updated: 最後にAPI等で名前を確認した日時
+// - nameSince: 現在の名前を最初に確認した日時
+type PlayerCacheEntry struct {
+	Id        string              `yaml:"id"`
+	Name      string              `yaml:"name"`
+	NameSince time.Time           `yaml:"nameSince,omitempty"`
+	Updated   time.Time           `yaml:"updated,omitempty"`
+	Aliases   []model.PlayerAlias `yaml:"aliases,omitempty"`
 }
 
 func LoadPlayerCache(path string) (*PlayerCache, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return &PlayerCache{
-			Players: make(map[string]model.PlayerProfile),
+			Players: make(map[string]PlayerCacheEntry),
 		}, nil
 	}
 
@@ -23,6 +36,9 @@ func LoadPlayerCache(path string) (*PlayerCache, error) {
 	if err := yaml.Unmarshal(b, &p); err != nil {
 		return nil, err
 	}
+	if p.Players == nil {
+		p.Players = make(map[string]PlayerCacheEntry)
+	}
 
 	return &p, nil
 }
@@ -45,3 +61,78 @@ func (pc PlayerCache) Save(path string) error {
 
 	return nil
 }
+
+func (e PlayerCacheEntry) Profile() model.PlayerProfile {
+	return model.PlayerProfile{
+		Id:      e.Id,
+		Name:    e.Name,
+		Aliases: e.Aliases,
+	}
+}
+
+// Fresh は ttl 以内に名前を確認しているかを返す (ttl が0の場合は無期限)
+func (e PlayerCacheEntry) Fresh(ttl time.Duration, now time.Time) bool {
+	return ttl <= 0 || now.Before(e.Updated.Add(ttl))
+}
+
+// Observe はプレイヤー名を記録する
+//   - 名前が変わっていた場合は、以前の名前を aliases に移す
+//   - confirmed: プロフィールAPIで確認した場合のみ確認日時 (Updated) を進める
+//     (usercache.json 等の未確認の名前は、TTL の間有効なものとして扱わない)
+func (pc PlayerCache) Observe(p model.PlayerProfile, now time.Time, confirmed bool) {
+	entry, exists := pc.Players[p.Id]
+	if !exists {
+		entry = PlayerCacheEntry{
+			Id:        p.Id,
+			Name:      p.Name,
+			NameSince: now,
+		}
+		if confirmed {
+			entry.Updated = now
+		}
+		pc.Players[p.Id] = entry
+		return
+	}
+
+	if entry.Name != p.Name {
+		// 以前の名前を最後に確認した日時 (未確認の場合は記録した日時)
+		lastSeen := entry.Updated
+		if lastSeen.IsZero() {
+			lastSeen = entry.NameSince
+		}
+
+		entry.Aliases = append(entry.Aliases, model.PlayerAlias{
+			Name:      entry.Name,
+			FirstSeen: entry.NameSince,
+			LastSeen:  lastSeen,
+		})
+		entry.Name = p.Name
+		entry.NameSince = now
+		// 確認日時は以前の名前のものなので引き継がない
+		entry.Updated = time.Time{}
+	}
+	if confirmed {
+		entry.Updated = now
+	}
+
+	pc.Players[p.Id] = entry
+}
+
+// FindByName は現在の名前が一致するプレイヤーと、以前の名前が一致するプレイヤーを返す (大文字小文字は区別しない)
+func (pc PlayerCache) FindByName(name string) (current []string, previous []string) {
+	for id, entry := range pc.Players {
+		if strings.EqualFold(entry.Name, name) {
+			current = append(current, id)
+			continue
+		}
+
+		for _, alias := range entry.Aliases {
+			if strings.EqualFold(alias.Name, name) {
+				previous = append(previous, id)
+				break
+			}
+		}
+	}
+
+	return current, previous
+}
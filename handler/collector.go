@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"image/png"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 
 	_collector "com.oykdn.mc-advancement-collector/collector"
 	"com.oykdn.mc-advancement-collector/config"
@@ -21,7 +26,7 @@ import (
 )
 
 const (
-	LANG_PATH = "./lang"
+	AVATAR_MAX_AGE = 3600 // 秒
 )
 
 var logger *_logger.ZapLogger = _logger.NewZapLogger()
@@ -36,12 +41,14 @@ func main() {
 		panic(err)
 	}
 
-	lang, err := lang.LoadLang(fmt.Sprintf("%s/%s.json", LANG_PATH, conf.AppConfig.Language))
+	languages, err := lang.LoadLanguages(lang.LANG_PATH, conf.AppConfig.Language, conf.AppConfig.Languages.FallbackLanguages(), conf.AppConfig.Languages.Available)
 	if err != nil {
 		panic(err)
 	}
 
-	collector := _collector.NewCollector(conf.AppConfig, conf.AdvancementList, lang, conf.PlayerCache)
+	validate, _ := binding.Validator.Engine().(*validator.Validate)
+
+	collector := _collector.NewCollector(conf.AppConfig, conf.AdvancementList, languages, conf.PlayerCache)
 
 	r := gin.New()
 
@@ -67,6 +74,58 @@ func main() {
 		MaxAge: 24 * time.Hour,
 	}))
 
+	// プレイヤー名・ハイフン無しのUUIDで指定された場合は、UUIDのURLへリダイレクトする
+	canonicalPlayer := func(c *gin.Context) {
+		id := c.Param("id")
+
+		resolved, err := collector.ResolvePlayerId(id)
+		if err != nil {
+			code := http.StatusInternalServerError
+
+			switch err {
+			case _collector.ErrPlayerNameNotFound:
+				code = http.StatusNotFound
+			case _collector.ErrPlayerNameAmbiguous:
+				code = http.StatusConflict
+			}
+
+			c.AbortWithStatusJSON(code, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if resolved != id {
+			u := *c.Request.URL
+			u.Path = strings.Replace(c.FullPath(), ":id", resolved, 1)
+			u.RawPath = ""
+
+			c.Redirect(http.StatusFound, u.String())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+
+	// ?lang= の指定が無い場合は Accept-Language から言語を選ぶ
+	selectLanguage := func(c *gin.Context, query string) (string, bool) {
+		l, err := languages.Select(query, c.GetHeader("Accept-Language"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return "", false
+		}
+
+		// Accept-Language で選んだ場合は、キャッシュが他の言語のレスポンスを返さないようにする
+		if query == "" {
+			c.Writer.Header().Add("Vary", "Accept-Language")
+		}
+		c.Header("Content-Language", l.Tag())
+		return l.Code, true
+	}
+
 	v1 := r.Group("/api/v1")
 	v1.GET("/healthcheck", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -75,7 +134,29 @@ func main() {
 	})
 
 	v1.GET("/players", func(c *gin.Context) {
-		p, err := collector.Player()
+		var q requests.PlayersRequest
+
+		if err := c.ShouldBindQuery(&q); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		language, ok := selectLanguage(c, q.Lang)
+		if !ok {
+			return
+		}
+
+		p, err := collector.Player(model.PlayersQuery{
+			Name:     q.Name,
+			Summary:  q.Summary,
+			Sort:     q.Sort,
+			Order:    q.Order,
+			Limit:    q.Limit,
+			Offset:   q.Offset,
+			Language: language,
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"message": err.Error(),
@@ -86,9 +167,174 @@ func main() {
 		c.IndentedJSON(http.StatusOK, p)
 	})
 
+	v1.GET("/players/:id/profile", canonicalPlayer, func(c *gin.Context) {
+		var p requests.PlayerProfileRequest
+
+		if err := c.ShouldBindUri(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		profile, err := collector.PlayerData(p.PlayerId)
+		if err != nil {
+			code := http.StatusInternalServerError
+
+			switch err {
+			case _collector.ErrPlayerDataNotFound:
+				code = http.StatusNotFound
+			}
+
+			c.JSON(code, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, profile)
+	})
+
+	v1.GET("/players/:id/avatar", canonicalPlayer, func(c *gin.Context) {
+		var p requests.PlayerAvatarRequest
+
+		if err := c.ShouldBindUri(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if err := c.ShouldBindQuery(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		avatar := collector.Avatar(p.PlayerId, model.AvatarQuery{
+			View: p.View,
+			Size: p.Size,
+		})
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, avatar); err != nil {
+			panic(err)
+		}
+
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", AVATAR_MAX_AGE))
+		c.Data(http.StatusOK, "image/png", buf.Bytes())
+	})
+
+	v1.GET("/leaderboard", func(c *gin.Context) {
+		var p requests.LeaderboardRequest
+
+		if err := c.ShouldBindQuery(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, collector.Leaderboard(model.LeaderboardQuery{
+			Type:   p.Type,
+			Limit:  p.Limit,
+			Offset: p.Offset,
+		}))
+	})
+
+	v1.GET("/advancements/stats", func(c *gin.Context) {
+		c.IndentedJSON(http.StatusOK, collector.AdvancementStats())
+	})
+
+	v1.GET("/compare", func(c *gin.Context) {
+		var p requests.CompareRequest
+
+		if err := c.ShouldBindQuery(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		// 2人以上, 重複なし, UUID形式であること
+		ids := p.PlayerIds()
+		if err := validate.Var(ids, fmt.Sprintf("min=2,max=%d,unique,dive,uuid", requests.CompareMaxPlayers)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		resp, err := collector.Compare(ids)
+		if err != nil {
+			code := http.StatusInternalServerError
+
+			switch err {
+			case _collector.ErrPlayerNotFound:
+				code = http.StatusNotFound
+			}
+
+			c.JSON(code, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, resp)
+	})
+
+	v1.GET("/diagnostics", func(c *gin.Context) {
+		c.IndentedJSON(http.StatusOK, collector.Diagnostics())
+	})
+
+	v1.GET("/stats/:id", canonicalPlayer, func(c *gin.Context) {
+		var p requests.PlayerStatsRequest
+
+		if err := c.ShouldBindUri(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if err := c.ShouldBindQuery(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		language, ok := selectLanguage(c, p.Lang)
+		if !ok {
+			return
+		}
+
+		stats, err := collector.LoadStats(p.PlayerId)
+		if err != nil {
+			code := http.StatusInternalServerError
+
+			switch err {
+			case _collector.ErrStatsNotFound:
+				code = http.StatusNotFound
+			}
+
+			c.JSON(code, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, collector.StatsResponse(language, p.Categories(), stats))
+	})
+
+	v1.GET("/languages", func(c *gin.Context) {
+		c.IndentedJSON(http.StatusOK, responses.ConvertToLanguagesResponse(languages))
+	})
+
 	advancement := v1.Group("/advancement")
 
-	advancement.GET("/:id", func(c *gin.Context) {
+	advancement.GET("/:id", canonicalPlayer, func(c *gin.Context) {
 		var p requests.PlayerAdvancementRequest
 
 		if err := c.ShouldBindUri(&p); err != nil {
@@ -100,8 +346,17 @@ func main() {
 
 		// クエリで条件を指定できるようにする
 		if err := c.ShouldBindQuery(&p); err != nil {
-			logger.Debug(err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		language, ok := selectLanguage(c, p.Lang)
+		if !ok {
+			return
 		}
+
 		condition := p.Condition
 		switch condition {
 		case model.ConditionAll:
@@ -132,7 +387,27 @@ func main() {
 			return
 		}
 
-		resp := collector.Response(collector.Filter(condition, advancements))
+		// at を指定した場合は、その時点の進捗に巻き戻す
+		if !p.At.IsZero() {
+			advancements = collector.At(p.At, advancements)
+		}
+
+		// format=tree の場合は親子関係の木構造で返す
+		filtered := collector.Filter(model.AdvancementFilter{
+			Condition: condition,
+			Tab:       p.Tab,
+		}, advancements)
+
+		// text=html, segments の場合はタイトル・説明の装飾も返す
+		filtered = collector.Text(language, p.Text, filtered)
+
+		var resp interface{}
+		switch p.Format {
+		case model.FormatTree:
+			resp = collector.Tree(filtered)
+		default:
+			resp = collector.Response(filtered)
+		}
 
 		// pretty print & no escape でJSONを返却
 		c.Status(http.StatusOK)
@@ -150,6 +425,46 @@ func main() {
 		}
 	})
 
+	advancement.GET("/:id/history", canonicalPlayer, func(c *gin.Context) {
+		var p requests.AdvancementHistoryRequest
+
+		if err := c.ShouldBindUri(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if err := c.ShouldBindQuery(&p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		resp, err := collector.History(p.PlayerId, model.AdvancementHistoryQuery{
+			From:   p.From,
+			To:     p.To,
+			Limit:  p.Limit,
+			Offset: p.Offset,
+		})
+		if err != nil {
+			code := http.StatusInternalServerError
+
+			switch err {
+			case _collector.ErrPlayerNotFound, _collector.ErrHistoryDisabled:
+				code = http.StatusNotFound
+			}
+
+			c.JSON(code, gin.H{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, resp)
+	})
+
 	advancement.GET("/assets", func(c *gin.Context) {
 		c.IndentedJSON(http.StatusOK, responses.ConvertToAdvancementAssetsResponse(conf.AppConfig.Assets.Background))
 	})
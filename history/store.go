@@ -0,0 +1,253 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	DefaultLimit = 100
+
+	openTimeout = 1 * time.Second
+)
+
+var (
+	// state/<uuid>/<key>\x00<criterion> -> 最後に観測したcriteriaの達成日時
+	// - criterion が空の場合は進捗自体の達成日時
+	bucketState = []byte("state")
+	// events/<uuid>/<time><seq> -> AdvancementHistoryEvent (JSON)
+	bucketEvents = []byte("events")
+)
+
+// Store は観測した進捗の変化をcriteria単位で記録する
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketState, bucketEvents} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record は前回観測した状態との差分をイベントとして記録する
+func (s *Store) Record(playerId string, advancements map[string]*model.PlayerAdvancement, observed time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		state, err := tx.Bucket(bucketState).CreateBucketIfNotExists([]byte(playerId))
+		if err != nil {
+			return err
+		}
+		events, err := tx.Bucket(bucketEvents).CreateBucketIfNotExists([]byte(playerId))
+		if err != nil {
+			return err
+		}
+
+		put := func(ev model.AdvancementHistoryEvent) error {
+			seq, err := events.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			b, err := json.Marshal(ev)
+			if err != nil {
+				return err
+			}
+
+			return events.Put(eventKey(ev.Time, seq), b)
+		}
+
+		// 達成・更新されたcriteriaを記録
+		seen := make(map[string]bool)
+		for key, adv := range advancements {
+			for criterion, t := range adv.Criteria {
+				if t == nil {
+					continue
+				}
+
+				k := stateKey(key, criterion)
+				seen[string(k)] = true
+
+				if prev := state.Get(k); prev != nil && bytes.Equal(prev, timeValue(*t)) {
+					continue
+				}
+				if err := state.Put(k, timeValue(*t)); err != nil {
+					return err
+				}
+				if err := put(model.AdvancementHistoryEvent{
+					Key:       key,
+					Criterion: criterion,
+					Event:     model.HistoryCriterionCompleted,
+					Time:      *t,
+					Observed:  observed,
+				}); err != nil {
+					return err
+				}
+			}
+
+			if !adv.Done {
+				continue
+			}
+
+			k := stateKey(key, "")
+			seen[string(k)] = true
+			if state.Get(k) != nil {
+				continue
+			}
+
+			t := observed
+			if completed := adv.CompletedAt(); completed != nil {
+				t = *completed
+			}
+			if err := state.Put(k, timeValue(t)); err != nil {
+				return err
+			}
+			if err := put(model.AdvancementHistoryEvent{
+				Key:      key,
+				Event:    model.HistoryAdvancementDone,
+				Time:     t,
+				Observed: observed,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// 前回存在し、今回消えたものは取り消し(/advancement revoke 等)として記録
+		var revoked [][]byte
+		if err := state.ForEach(func(k, _ []byte) error {
+			key, _, _ := bytes.Cut(k, []byte{0})
+			if _, exists := advancements[string(key)]; exists && !seen[string(k)] {
+				revoked = append(revoked, append([]byte(nil), k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range revoked {
+			key, criterion, _ := bytes.Cut(k, []byte{0})
+
+			ev := model.AdvancementHistoryEvent{
+				Key:       string(key),
+				Criterion: string(criterion),
+				Event:     model.HistoryCriterionRevoked,
+				Time:      observed,
+				Observed:  observed,
+			}
+			if len(criterion) == 0 {
+				ev.Event = model.HistoryAdvancementUndone
+			}
+
+			if err := state.Delete(k); err != nil {
+				return err
+			}
+			if err := put(ev); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Timeline は期間内のイベントを時系列順に返す (ページング前の総件数も返す)
+func (s *Store) Timeline(playerId string, query model.AdvancementHistoryQuery) ([]model.AdvancementHistoryEvent, int, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	result := []model.AdvancementHistoryEvent{}
+	total := 0
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		events := tx.Bucket(bucketEvents).Bucket([]byte(playerId))
+		if events == nil {
+			return nil
+		}
+
+		var min []byte
+		if !query.From.IsZero() {
+			min = eventKey(query.From, 0)
+		}
+
+		cur := events.Cursor()
+		k, v := cur.First()
+		if min != nil {
+			k, v = cur.Seek(min)
+		}
+		for ; k != nil; k, v = cur.Next() {
+			if !query.To.IsZero() && eventTime(k).After(query.To) {
+				break
+			}
+
+			total += 1
+			if total <= query.Offset || len(result) >= limit {
+				continue
+			}
+
+			var ev model.AdvancementHistoryEvent
+			if err := json.Unmarshal(v, &ev); err != nil {
+				return err
+			}
+			result = append(result, ev)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
+
+func stateKey(key, criterion string) []byte {
+	return []byte(key + "\x00" + criterion)
+}
+
+func timeValue(t time.Time) []byte {
+	return []byte(t.UTC().Format(time.RFC3339Nano))
+}
+
+// eventKey は時刻順に並ぶよう、UnixNano(BE) + シーケンス番号(BE)をキーにする
+func eventKey(t time.Time, seq uint64) []byte {
+	k := make([]byte, 16)
+	binary.BigEndian.PutUint64(k[:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint64(k[8:], seq)
+	return k
+}
+
+func eventTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k[:8])))
+}
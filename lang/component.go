@@ -0,0 +1,138 @@
+package lang
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Component はゲームのテキストコンポーネント (進捗のタイトル・説明等)
+// - 文字列・配列の形式も読み込める (配列は先頭の要素に残りを extra として続ける)
+type Component struct {
+	Text          string      `json:"text,omitempty"`
+	Translate     string      `json:"translate,omitempty"`
+	Fallback      string      `json:"fallback,omitempty"`
+	With          []Component `json:"with,omitempty"`
+	Keybind       string      `json:"keybind,omitempty"`
+	Color         string      `json:"color,omitempty"`
+	Bold          *bool       `json:"bold,omitempty"`
+	Italic        *bool       `json:"italic,omitempty"`
+	Underlined    *bool       `json:"underlined,omitempty"`
+	Strikethrough *bool       `json:"strikethrough,omitempty"`
+	Obfuscated    *bool       `json:"obfuscated,omitempty"`
+	Extra         []Component `json:"extra,omitempty"`
+}
+
+type componentObject Component
+
+func (c *Component) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil
+	}
+
+	switch b[0] {
+	case '"':
+		*c = Component{}
+		return json.Unmarshal(b, &c.Text)
+
+	case '[':
+		var list []Component
+		if err := json.Unmarshal(b, &list); err != nil {
+			return err
+		}
+
+		*c = Component{}
+		if len(list) > 0 {
+			*c = list[0]
+			c.Extra = append(c.Extra, list[1:]...)
+		}
+		return nil
+
+	case '{':
+		var obj componentObject
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return err
+		}
+
+		*c = Component(obj)
+		return nil
+	}
+
+	// 数値・真偽値はそのまま文字列として扱う
+	*c = Component{
+		Text: string(b),
+	}
+	return nil
+}
+
+// UnmarshalYAML は advancementlist.yml に JSON と同じ形式で書けるようにする
+func (c *Component) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return err
+	}
+
+	return c.UnmarshalJSON(b)
+}
+
+func (c Component) MarshalYAML() (interface{}, error) {
+	if c.plain() {
+		return c.Text, nil
+	}
+
+	b, err := json.Marshal(componentObject(c))
+	if err != nil {
+		return nil, err
+	}
+
+	var v map[string]interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// TranslateKey は翻訳キーのみのコンポーネントの場合にキーを返す
+func (c Component) TranslateKey() (string, bool) {
+	if c.Translate == "" || !c.unstyled() || c.Text != "" || c.Fallback != "" || len(c.With) > 0 || len(c.Extra) > 0 {
+		return "", false
+	}
+
+	return c.Translate, true
+}
+
+// plain は装飾の無い文字列のみのコンポーネントかを返す
+func (c Component) plain() bool {
+	return c.unstyled() && c.Translate == "" && c.Keybind == "" && len(c.With) == 0 && len(c.Extra) == 0
+}
+
+func (c Component) unstyled() bool {
+	return c.Color == "" && c.Bold == nil && c.Italic == nil && c.Underlined == nil && c.Strikethrough == nil && c.Obfuscated == nil
+}
+
+// jsonValue は yaml.v2 の map[interface{}]interface{} を JSON に変換できる形にする
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonValue(e)
+		}
+		return v
+	}
+
+	return v
+}
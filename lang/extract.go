@@ -0,0 +1,182 @@
+package lang
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// 言語ファイルの置き場所 (<言語コード>.json)
+	LANG_PATH = "./lang"
+
+	assetLangPrefix = "minecraft/lang/"
+	jarLangPrefix   = "assets/" + assetLangPrefix
+)
+
+var (
+	ErrAssetIndexNotFound = fmt.Errorf("asset index not found")
+	ErrAssetHashMismatch  = fmt.Errorf("asset hash mismatch")
+)
+
+// assetIndex は .minecraft/assets/indexes/<バージョン>.json
+type assetIndex struct {
+	Objects map[string]struct {
+		Hash string `json:"hash"`
+		Size int64  `json:"size"`
+	} `json:"objects"`
+}
+
+// AssetIndexes は .minecraft/assets/indexes/ のインデックス名を新しい順に返す
+func AssetIndexes(assets string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(assets, "indexes"))
+	if err != nil {
+		return nil, err
+	}
+
+	type index struct {
+		name    string
+		modTime int64
+	}
+	var indexes []index
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index{
+			name:    strings.TrimSuffix(e.Name(), ".json"),
+			modTime: info.ModTime().UnixNano(),
+		})
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].modTime > indexes[j].modTime
+	})
+
+	names := make([]string, len(indexes))
+	for i, v := range indexes {
+		names[i] = v.name
+	}
+
+	return names, nil
+}
+
+// ExtractAssets は .minecraft/assets のインデックスとハッシュ名のオブジェクトから言語ファイルを取り出す
+// - 戻り値は 言語コード -> 言語ファイルの中身
+// - index が空の場合は最も新しいインデックスを使う
+// - en_us はクライアントの jar にのみ含まれる
+func ExtractAssets(assets, index string) (map[string][]byte, error) {
+	if index == "" {
+		indexes, err := AssetIndexes(assets)
+		if err != nil {
+			return nil, err
+		}
+		if len(indexes) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrAssetIndexNotFound, assets)
+		}
+		index = indexes[0]
+	}
+
+	b, err := os.ReadFile(filepath.Join(assets, "indexes", index+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var idx assetIndex
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return nil, fmt.Errorf("%s: %w", index, err)
+	}
+
+	files := make(map[string][]byte)
+	for name, object := range idx.Objects {
+		code, ok := langCode(name, assetLangPrefix)
+		if !ok || len(object.Hash) < 2 {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(assets, "objects", object.Hash[:2], object.Hash))
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha1.Sum(b)
+		if hex.EncodeToString(sum[:]) != strings.ToLower(object.Hash) {
+			return nil, fmt.Errorf("%w: %s", ErrAssetHashMismatch, name)
+		}
+
+		files[code] = b
+	}
+
+	return files, nil
+}
+
+// ExtractJar はクライアントの jar (assets/minecraft/lang/) から言語ファイルを取り出す
+func ExtractJar(filename string) (map[string][]byte, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	files := make(map[string][]byte)
+	for _, f := range r.File {
+		code, ok := langCode(f.Name, jarLangPrefix)
+		if !ok {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files[code] = b
+	}
+
+	return files, nil
+}
+
+// langCode は言語ファイルのパスから言語コードを返す
+// - 1.13 より前の .lang 形式は対象外
+func langCode(name, prefix string) (string, bool) {
+	if !strings.HasPrefix(name, prefix) || path.Ext(name) != ".json" {
+		return "", false
+	}
+
+	code := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json")
+	if code == "" || strings.Contains(code, "/") {
+		return "", false
+	}
+
+	return strings.ToLower(code), true
+}
+
+// ParseLang は言語ファイルの中身を読み込む
+func ParseLang(b []byte) (*Lang, error) {
+	var mapping map[string]string
+	if err := json.Unmarshal(b, &mapping); err != nil {
+		return nil, err
+	}
+
+	return &Lang{
+		Mapping: mapping,
+	}, nil
+}
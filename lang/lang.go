@@ -1,7 +1,6 @@
 package lang
 
 import (
-	"encoding/json"
 	"os"
 )
 
@@ -11,7 +10,11 @@ const (
 )
 
 type Lang struct {
+	Code    string
 	Mapping map[string]string
+
+	// 翻訳が無い場合に引く言語 (最後は nil)
+	Fallback *Lang
 }
 
 func LoadLang(path string) (*Lang, error) {
@@ -20,12 +23,16 @@ func LoadLang(path string) (*Lang, error) {
 		return nil, err
 	}
 
-	var mapping map[string]string
-	if err := json.Unmarshal(b, &mapping); err != nil {
-		return nil, err
+	return ParseLang(b)
+}
+
+// Lookup は翻訳を返す (無い場合は Fallback の言語を順に引く)
+func (l *Lang) Lookup(key string) (string, bool) {
+	for ; l != nil; l = l.Fallback {
+		if v, exists := l.Mapping[key]; exists {
+			return v, true
+		}
 	}
 
-	return &Lang{
-		Mapping: mapping,
-	}, err
+	return "", false
 }
@@ -0,0 +1,212 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// 言語ファイルに含まれる言語の名前・地域のキー
+	LANG_KEY_NAME   = "language.name"
+	LANG_KEY_REGION = "language.region"
+)
+
+var (
+	ErrLanguageNotFound = fmt.Errorf("language not found")
+)
+
+// Languages はリクエストごとに選べる言語
+// - 翻訳が無い場合は fallback の言語を順に引き、最後はキーそのまま
+type Languages struct {
+	Default  string
+	Fallback []string
+
+	langs map[string]*Lang
+	codes []string
+}
+
+// LoadLanguages は dir/<言語コード>.json を読み込む
+// - codes が空の場合は既定の言語と fallback の言語のみ
+// - fallback の言語が見つからない場合は fallback から除く
+func LoadLanguages(dir string, def string, fallback []string, codes []string) (*Languages, error) {
+	def = normalizeCode(def)
+
+	ls := &Languages{
+		Default: def,
+		langs:   make(map[string]*Lang),
+	}
+
+	load := func(code string, required bool) error {
+		code = normalizeCode(code)
+		if _, exists := ls.langs[code]; exists || code == "" {
+			return nil
+		}
+
+		l, err := LoadLang(filepath.Join(dir, code+".json"))
+		if err != nil {
+			if !required && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", code, err)
+		}
+
+		l.Code = code
+		ls.langs[code] = l
+		ls.codes = append(ls.codes, code)
+		return nil
+	}
+
+	if err := load(def, true); err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		if err := load(code, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, code := range fallback {
+		if err := load(code, false); err != nil {
+			return nil, err
+		}
+		if _, exists := ls.langs[normalizeCode(code)]; exists && !contains(ls.Fallback, normalizeCode(code)) {
+			ls.Fallback = append(ls.Fallback, normalizeCode(code))
+		}
+	}
+	sort.Strings(ls.codes)
+
+	// fallback の言語は次の fallback へ、それ以外の言語は fallback の先頭へつなぐ
+	for code, l := range ls.langs {
+		next := 0
+		for i, f := range ls.Fallback {
+			if f == code {
+				next = i + 1
+			}
+		}
+
+		for ; next < len(ls.Fallback); next++ {
+			if f := ls.Fallback[next]; f != code {
+				l.Fallback = ls.langs[f]
+				break
+			}
+		}
+	}
+
+	return ls, nil
+}
+
+// Codes は選べる言語の言語コードを返す
+func (ls *Languages) Codes() []string {
+	return ls.codes
+}
+
+// Get は言語コードの言語を返す (無い場合は既定の言語)
+func (ls *Languages) Get(code string) *Lang {
+	if l, exists := ls.langs[normalizeCode(code)]; exists {
+		return l
+	}
+
+	return ls.langs[ls.Default]
+}
+
+// Select はリクエストの言語を選ぶ
+// - query (?lang=) の指定がある場合はその言語 (無い場合は ErrLanguageNotFound)
+// - 無い場合は Accept-Language の優先度の高いものから、同じ言語 (ja-JP -> ja_jp) か同じ言語の地域違い (ja -> ja_jp)
+// - どれも無い場合は既定の言語
+func (ls *Languages) Select(query, acceptLanguage string) (*Lang, error) {
+	if query != "" {
+		l, exists := ls.langs[normalizeCode(query)]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrLanguageNotFound, query)
+		}
+		return l, nil
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if l, exists := ls.langs[normalizeCode(tag)]; exists {
+			return l, nil
+		}
+
+		language, _, _ := strings.Cut(normalizeCode(tag), "_")
+		for _, code := range ls.codes {
+			if strings.HasPrefix(code, language+"_") {
+				return ls.langs[code], nil
+			}
+		}
+	}
+
+	return ls.langs[ls.Default], nil
+}
+
+// Name は言語の名前と地域を返す (言語ファイルの language.name, language.region)
+func (l *Lang) Name() (string, string) {
+	return l.Mapping[LANG_KEY_NAME], l.Mapping[LANG_KEY_REGION]
+}
+
+// Tag は言語コードを ja-JP 形式の言語タグで返す (Content-Language 用)
+func (l *Lang) Tag() string {
+	language, region, found := strings.Cut(l.Code, "_")
+	if !found {
+		return language
+	}
+	return language + "-" + strings.ToUpper(region)
+}
+
+// normalizeCode は ja-JP 形式の言語タグを ja_jp 形式にする
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", "_"))
+}
+
+// parseAcceptLanguage は Accept-Language の言語タグを優先度 (q) の高い順に返す
+func parseAcceptLanguage(header string) []string {
+	type tag struct {
+		name string
+		q    float64
+	}
+
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name == "" || name == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		tags = append(tags, tag{name: name, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+
+	return names
+}
+
+func contains(list []string, v string) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package lang
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	formattingCode = '§'
+)
+
+// 色の名前と § の色コード (0-f) の順序は同じ
+var (
+	colorNames = []string{
+		"black", "dark_blue", "dark_green", "dark_aqua", "dark_red", "dark_purple", "gold", "gray",
+		"dark_gray", "blue", "green", "aqua", "red", "light_purple", "yellow", "white",
+	}
+	colorValues = []string{
+		"#000000", "#0000AA", "#00AA00", "#00AAAA", "#AA0000", "#AA00AA", "#FFAA00", "#AAAAAA",
+		"#555555", "#5555FF", "#55FF55", "#55FFFF", "#FF5555", "#FF55FF", "#FFFF55", "#FFFFFF",
+	}
+
+	// %s, %1$s, %% (Javaの String.format と同じ)
+	formatPattern = regexp.MustCompile(`%(?:(\d+)\$)?([sd%])`)
+)
+
+type style struct {
+	color         string
+	bold          bool
+	italic        bool
+	underlined    bool
+	strikethrough bool
+	obfuscated    bool
+}
+
+func (s style) apply(c Component) style {
+	if c.Color != "" {
+		s.color = colorValue(c.Color)
+	}
+
+	for _, v := range []struct {
+		src *bool
+		dst *bool
+	}{
+		{c.Bold, &s.bold},
+		{c.Italic, &s.italic},
+		{c.Underlined, &s.underlined},
+		{c.Strikethrough, &s.strikethrough},
+		{c.Obfuscated, &s.obfuscated},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+
+	return s
+}
+
+// Render はテキストコンポーネントを装飾ごとの区間に分ける
+// - translate は言語ファイルから引き (無い場合は Fallback の言語, コンポーネントの fallback, キーの順)、with の値を埋め込む
+// - 文字列に含まれる § の書式コードも装飾として扱う
+func (l *Lang) Render(c Component) []model.TextSegment {
+	r := &renderer{
+		lang: l,
+	}
+	r.component(c, style{}, 0)
+
+	return r.segments
+}
+
+// Translate は翻訳キーの文字列を書式コードを除いて返す
+func (l *Lang) Translate(key string) (string, bool) {
+	if _, exists := l.Lookup(key); !exists {
+		return "", false
+	}
+
+	return Plain(l.Render(Component{Translate: key})), true
+}
+
+// Plain は装飾を除いた文字列を返す
+func Plain(segments []model.TextSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString(s.Text)
+	}
+
+	return b.String()
+}
+
+// HTML は装飾を span の style で表した HTML を返す
+func HTML(segments []model.TextSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		var css []string
+		if s.Color != "" {
+			css = append(css, "color:"+s.Color)
+		}
+		if s.Bold {
+			css = append(css, "font-weight:bold")
+		}
+		if s.Italic {
+			css = append(css, "font-style:italic")
+		}
+
+		var decorations []string
+		if s.Underlined {
+			decorations = append(decorations, "underline")
+		}
+		if s.Strikethrough {
+			decorations = append(decorations, "line-through")
+		}
+		if len(decorations) > 0 {
+			css = append(css, "text-decoration:"+strings.Join(decorations, " "))
+		}
+
+		text := strings.ReplaceAll(html.EscapeString(s.Text), "\n", "<br>")
+		if len(css) == 0 && !s.Obfuscated {
+			b.WriteString(text)
+			continue
+		}
+
+		b.WriteString("<span")
+		if s.Obfuscated {
+			b.WriteString(` class="obfuscated"`)
+		}
+		if len(css) > 0 {
+			b.WriteString(` style="` + strings.Join(css, ";") + `"`)
+		}
+		b.WriteString(">" + text + "</span>")
+	}
+
+	return b.String()
+}
+
+type renderer struct {
+	lang     *Lang
+	segments []model.TextSegment
+}
+
+// 循環した with 等で無限に再帰しないための上限
+const maxComponentDepth = 32
+
+func (r *renderer) component(c Component, parent style, depth int) {
+	if depth > maxComponentDepth {
+		return
+	}
+
+	s := parent.apply(c)
+
+	switch {
+	case c.Translate != "":
+		r.translate(c, s, depth)
+	case c.Keybind != "":
+		r.text(r.lookup(c.Keybind, c.Keybind), s)
+	default:
+		r.text(c.Text, s)
+	}
+
+	for _, e := range c.Extra {
+		r.component(e, s, depth+1)
+	}
+}
+
+func (r *renderer) translate(c Component, s style, depth int) {
+	fallback := c.Fallback
+	if fallback == "" {
+		fallback = c.Translate
+	}
+	format := r.lookup(c.Translate, fallback)
+
+	next := 0
+	for {
+		loc := formatPattern.FindStringSubmatchIndex(format)
+		if loc == nil {
+			r.text(format, s)
+			return
+		}
+
+		r.text(format[:loc[0]], s)
+
+		verb := format[loc[4]:loc[5]]
+		if verb == "%" {
+			r.text("%", s)
+		} else {
+			i := next
+			if loc[2] >= 0 {
+				n, _ := strconv.Atoi(format[loc[2]:loc[3]])
+				i = n - 1
+			} else {
+				next++
+			}
+
+			if i >= 0 && i < len(c.With) {
+				r.component(c.With[i], s, depth+1)
+			}
+		}
+
+		format = format[loc[1]:]
+	}
+}
+
+func (r *renderer) lookup(key, fallback string) string {
+	if v, exists := r.lang.Lookup(key); exists {
+		return v
+	}
+
+	return fallback
+}
+
+// text は § の書式コードを解釈しながら区間を追加する
+// - 色コードはそれまでの書式を解除し、r はコンポーネントの装飾に戻す
+func (r *renderer) text(text string, base style) {
+	s := base
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != formattingCode || i+1 >= len(runes) {
+			b.WriteRune(runes[i])
+			continue
+		}
+
+		r.append(b.String(), s)
+		b.Reset()
+
+		i++
+		code := strings.ToLower(string(runes[i]))
+		if n := strings.Index("0123456789abcdef", code); n >= 0 {
+			s = style{color: colorValues[n]}
+			continue
+		}
+
+		switch code {
+		case "k":
+			s.obfuscated = true
+		case "l":
+			s.bold = true
+		case "m":
+			s.strikethrough = true
+		case "n":
+			s.underlined = true
+		case "o":
+			s.italic = true
+		case "r":
+			s = base
+		}
+	}
+
+	r.append(b.String(), s)
+}
+
+// append は同じ装飾の区間が続く場合はまとめる
+func (r *renderer) append(text string, s style) {
+	if text == "" {
+		return
+	}
+
+	segment := model.TextSegment{
+		Text:          text,
+		Color:         s.color,
+		Bold:          s.bold,
+		Italic:        s.italic,
+		Underlined:    s.underlined,
+		Strikethrough: s.strikethrough,
+		Obfuscated:    s.obfuscated,
+	}
+
+	if n := len(r.segments); n > 0 && sameStyle(r.segments[n-1], segment) {
+		r.segments[n-1].Text += text
+		return
+	}
+
+	r.segments = append(r.segments, segment)
+}
+
+func sameStyle(a, b model.TextSegment) bool {
+	a.Text, b.Text = "", ""
+	return a == b
+}
+
+// colorValue は色の名前を #RRGGBB に変換する (#RRGGBB はそのまま)
+func colorValue(color string) string {
+	for i, name := range colorNames {
+		if name == color {
+			return colorValues[i]
+		}
+	}
+
+	if strings.HasPrefix(color, "#") {
+		return strings.ToUpper(color)
+	}
+
+	return ""
+}
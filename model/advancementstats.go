@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type AdvancementStats struct {
+	Key        string                 `json:"key"`
+	Completed  int                    `json:"completed"`
+	Percentage float64                `json:"percentage"`
+	First      *AdvancementStatsFirst `json:"first"`
+}
+
+type AdvancementStatsFirst struct {
+	Player PlayerProfile `json:"player"`
+	Time   time.Time     `json:"time"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type AdvancementComparison struct {
+	Key   string                `json:"key"`
+	Done  map[string]*time.Time `json:"done"`
+	First string                `json:"first,omitempty"`
+}
+
+type PlayerComparison struct {
+	Player   PlayerProfile       `json:"player"`
+	Progress AdvancementProgress `json:"progress"`
+	Unique   []string            `json:"unique"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type CriterionDiagnostic struct {
+	Key       string   `json:"key"`
+	Criterion string   `json:"criterion"`
+	Players   []string `json:"players"`
+}
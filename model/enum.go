@@ -13,6 +13,8 @@ type MetricsType string
 const (
 	MetricsAnyOf MetricsType = "anyof"
 	MetricsAllOf MetricsType = "allof"
+	// criteriaのグループ(OR)を全て(AND)満たすことが条件
+	MetricsRequirements MetricsType = "requirements"
 )
 
 type AdvancementFilterCondition string
@@ -22,3 +24,73 @@ const (
 	ConditionDone     AdvancementFilterCondition = "done"
 	ConditionProgress AdvancementFilterCondition = "progress"
 )
+
+type AdvancementFilter struct {
+	Condition AdvancementFilterCondition
+	Tab       string
+}
+
+type HistoryEventType string
+
+const (
+	HistoryCriterionCompleted HistoryEventType = "criterion"
+	HistoryCriterionRevoked   HistoryEventType = "revoked"
+	HistoryAdvancementDone    HistoryEventType = "done"
+	HistoryAdvancementUndone  HistoryEventType = "undone"
+)
+
+type ResponseFormat string
+
+const (
+	FormatList ResponseFormat = "list"
+	FormatTree ResponseFormat = "tree"
+)
+
+type TextFormat string
+
+const (
+	TextPlain    TextFormat = "plain"
+	TextHTML     TextFormat = "html"
+	TextSegments TextFormat = "segments"
+)
+
+type GameMode string
+
+const (
+	GameModeSurvival  GameMode = "survival"
+	GameModeCreative  GameMode = "creative"
+	GameModeAdventure GameMode = "adventure"
+	GameModeSpectator GameMode = "spectator"
+)
+
+type AvatarView string
+
+const (
+	AvatarViewFace AvatarView = "face"
+	AvatarViewBust AvatarView = "bust"
+	AvatarViewBody AvatarView = "body"
+)
+
+type AccountType string
+
+const (
+	AccountOnline    AccountType = "online"
+	AccountOffline   AccountType = "offline"
+	AccountFloodgate AccountType = "floodgate"
+	AccountUnknown   AccountType = "unknown"
+)
+
+type PlayerSort string
+
+const (
+	PlayerSortName     PlayerSort = "name"
+	PlayerSortProgress PlayerSort = "progress"
+	PlayerSortActivity PlayerSort = "activity"
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
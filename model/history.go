@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type AdvancementHistoryEvent struct {
+	Key       string           `json:"key"`
+	Criterion string           `json:"criterion,omitempty"`
+	Event     HistoryEventType `json:"event"`
+	Time      time.Time        `json:"time"`
+	Observed  time.Time        `json:"observed"`
+}
+
+type AdvancementHistoryQuery struct {
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
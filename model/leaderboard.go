@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type LeaderboardEntry struct {
+	Rank     int                 `json:"rank"`
+	Player   PlayerProfile       `json:"player"`
+	Progress AdvancementProgress `json:"progress"`
+	Latest   *time.Time          `json:"latest"`
+}
+
+type LeaderboardQuery struct {
+	Type   AdvancementType
+	Limit  int
+	Offset int
+}
@@ -5,25 +5,48 @@ import "time"
 type PlayerAdvancementSummary struct {
 	Advancements map[string]*PlayerAdvancement
 	Progress     AdvancementProgress
+	Tabs         map[string]AdvancementProgress
 	Updated      time.Time
 	Cached       time.Time
 }
 type PlayerAdvancement struct {
-	Key      string                   `json:"key"`
-	Parent   string                   `json:"parent"`
-	Display  PlayerAdvancementDisplay `json:"display"`
-	Type     AdvancementType          `json:"type"`
-	Hidden   bool                     `json:"hidden"`
-	Done     bool                     `json:"done"`
-	Metrics  MetricsType              `json:"metrics"`
-	Criteria map[string]*time.Time    `json:"criteria"`
-	Progress AdvancementProgress      `json:"progress"`
+	Key           string                   `json:"key"`
+	Parent        string                   `json:"parent"`
+	Display       PlayerAdvancementDisplay `json:"display"`
+	Type          AdvancementType          `json:"type"`
+	Tab           string                   `json:"tab"`
+	Namespace     string                   `json:"namespace"`
+	Source        string                   `json:"source,omitempty"`
+	Hidden        bool                     `json:"hidden"`
+	Done          bool                     `json:"done"`
+	Metrics       MetricsType              `json:"metrics"`
+	Criteria      map[string]*time.Time    `json:"criteria"`
+	ExtraCriteria map[string]*time.Time    `json:"extraCriteria,omitempty"`
+	Progress      AdvancementProgress      `json:"progress"`
+	Rarity        *float64                 `json:"rarity,omitempty"`
 }
 
 type PlayerAdvancementDisplay struct {
-	Title       string                       `json:"title"`
-	Description string                       `json:"description"`
-	Icon        PlayerAdvancementDisplayIcon `json:"icon"`
+	Title       string                            `json:"title"`
+	Description string                            `json:"description"`
+	Segments    *PlayerAdvancementDisplaySegments `json:"segments,omitempty"`
+	Icon        PlayerAdvancementDisplayIcon      `json:"icon"`
+}
+
+// PlayerAdvancementDisplaySegments は装飾 (色・太字等) ごとに分けたタイトルと説明
+type PlayerAdvancementDisplaySegments struct {
+	Title       []TextSegment `json:"title"`
+	Description []TextSegment `json:"description"`
+}
+
+type TextSegment struct {
+	Text          string `json:"text"`
+	Color         string `json:"color,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Underlined    bool   `json:"underlined,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+	Obfuscated    bool   `json:"obfuscated,omitempty"`
 }
 
 type PlayerAdvancementDisplayIcon struct {
@@ -32,3 +55,25 @@ type PlayerAdvancementDisplayIcon struct {
 	PosX      *int   `json:"posx,omitempty"`
 	PosY      *int   `json:"posy,omitempty"`
 }
+
+// CompletedAt は達成済みの進捗について、最後に達成したcriteriaの日時を返す
+func (a *PlayerAdvancement) CompletedAt() *time.Time {
+	if !a.Done {
+		return nil
+	}
+
+	var latest *time.Time
+	for _, t := range a.Criteria {
+		if t != nil && (latest == nil || t.After(*latest)) {
+			latest = t
+		}
+	}
+
+	return latest
+}
+
+type PlayerAdvancementNode struct {
+	*PlayerAdvancement
+	Subtree  AdvancementProgress      `json:"subtree"`
+	Children []*PlayerAdvancementNode `json:"children"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type PlayerDataProfile struct {
+	XpLevel        int64           `json:"xpLevel"`
+	XpProgress     float64         `json:"xpProgress"`
+	XpTotal        int64           `json:"xpTotal"`
+	Health         float64         `json:"health"`
+	FoodLevel      int64           `json:"foodLevel"`
+	FoodSaturation float64         `json:"foodSaturation"`
+	GameMode       GameMode        `json:"gameMode"`
+	Dimension      string          `json:"dimension"`
+	Position       *PlayerPosition `json:"position,omitempty"`
+	Updated        time.Time       `json:"updated"`
+	Cached         time.Time       `json:"cached"`
+}
+
+type PlayerPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
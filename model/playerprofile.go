@@ -1,11 +1,103 @@
 package model
 
+import (
+	"strings"
+	"time"
+)
+
 type PlayerProfile struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id      string        `json:"id"`
+	Name    string        `json:"name"`
+	Type    AccountType   `json:"type,omitempty"`
+	Xuid    string        `json:"xuid,omitempty"`
+	Aliases []PlayerAlias `json:"aliases,omitempty"`
+	Skin    *PlayerSkin   `json:"-"`
+}
+
+// PlayerSkin はセッションサーバーの textures プロパティから取得したスキン
+type PlayerSkin struct {
+	Url  string
+	Slim bool
+}
+
+type PlayerAlias struct {
+	Name      string    `json:"name" yaml:"name"`
+	FirstSeen time.Time `json:"firstSeen" yaml:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen" yaml:"lastSeen"`
 }
 
 type MojangPlayerProfile struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id         string                  `json:"id"`
+	Name       string                  `json:"name"`
+	Properties []MojangProfileProperty `json:"properties"`
+}
+
+type MojangProfileProperty struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature"`
+}
+
+// MojangTextures は textures プロパティ (base64) の中身
+type MojangTextures struct {
+	Timestamp   int64                    `json:"timestamp"`
+	ProfileId   string                   `json:"profileId"`
+	ProfileName string                   `json:"profileName"`
+	Textures    map[string]MojangTexture `json:"textures"`
+}
+
+type MojangTexture struct {
+	Url      string `json:"url"`
+	Metadata struct {
+		Model string `json:"model"`
+	} `json:"metadata"`
+}
+
+type AvatarQuery struct {
+	View AvatarView
+	Size int
+}
+
+// PlayerListEntry は一覧のプレイヤー (summary は指定された場合のみ)
+type PlayerListEntry struct {
+	PlayerProfile
+	Summary *PlayerListSummary `json:"summary,omitempty"`
+}
+
+type PlayerListSummary struct {
+	Progress     AdvancementProgress      `json:"progress"`
+	LastActivity time.Time                `json:"lastActivity"`
+	Latest       *PlayerLatestAdvancement `json:"latest"`
+}
+
+type PlayerLatestAdvancement struct {
+	Key         string    `json:"key"`
+	Title       string    `json:"title"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+type PlayersQuery struct {
+	Name    string
+	Summary bool
+	Sort    PlayerSort
+	Order   SortOrder
+	Limit   int
+	Offset  int
+	// 最後に達成した進捗のタイトルの言語
+	Language string
+}
+
+// MatchName は現在の名前、または以前の名前が一致するかを返す (大文字小文字は区別しない)
+func (p PlayerProfile) MatchName(name string) bool {
+	if strings.EqualFold(p.Name, name) {
+		return true
+	}
+
+	for _, alias := range p.Aliases {
+		if strings.EqualFold(alias.Name, name) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type MinecraftStats struct {
+	Stats       map[string]map[string]int64 `json:"stats"`
+	DataVersion int                         `json:"DataVersion"`
+}
+
+type PlayerStatsSummary struct {
+	Categories map[string]*PlayerStatsCategory
+	Updated    time.Time
+	Cached     time.Time
+}
+
+type PlayerStatsCategory struct {
+	Key   string            `json:"key"`
+	Name  string            `json:"name"`
+	Stats []PlayerStatistic `json:"stats"`
+}
+
+type PlayerStatistic struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
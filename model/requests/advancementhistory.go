@@ -0,0 +1,11 @@
+package requests
+
+import "time"
+
+type AdvancementHistoryRequest struct {
+	PlayerId string    `uri:"id" binding:"required,uuid"`
+	From     time.Time `form:"from"`
+	To       time.Time `form:"to"`
+	Limit    int       `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset   int       `form:"offset" binding:"omitempty,min=0"`
+}
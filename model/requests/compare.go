@@ -0,0 +1,23 @@
+package requests
+
+import "strings"
+
+const (
+	CompareMaxPlayers = 10
+)
+
+type CompareRequest struct {
+	Players string `form:"players" binding:"required"`
+}
+
+// PlayerIds はカンマ区切りで指定されたプレイヤーIDを小文字にして返す (大文字・小文字違いの重複を検出するため)
+func (r CompareRequest) PlayerIds() []string {
+	var ids []string
+	for _, id := range strings.Split(r.Players, ",") {
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
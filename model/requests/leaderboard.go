@@ -0,0 +1,9 @@
+package requests
+
+import "com.oykdn.mc-advancement-collector/model"
+
+type LeaderboardRequest struct {
+	Type   model.AdvancementType `form:"type" binding:"omitempty,oneof=task goal challenge"`
+	Limit  int                   `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset int                   `form:"offset" binding:"omitempty,min=0"`
+}
@@ -1,8 +1,17 @@
 package requests
 
-import "com.oykdn.mc-advancement-collector/model"
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
 
 type PlayerAdvancementRequest struct {
 	PlayerId  string                           `uri:"id" binding:"required,uuid"`
 	Condition model.AdvancementFilterCondition `form:"condition"`
+	Tab       string                           `form:"tab"`
+	Format    model.ResponseFormat             `form:"format"`
+	Text      model.TextFormat                 `form:"text" binding:"omitempty,oneof=plain html segments"`
+	At        time.Time                        `form:"at"`
+	Lang      string                           `form:"lang"`
 }
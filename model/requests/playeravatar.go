@@ -0,0 +1,9 @@
+package requests
+
+import "com.oykdn.mc-advancement-collector/model"
+
+type PlayerAvatarRequest struct {
+	PlayerId string           `uri:"id" binding:"required,uuid"`
+	View     model.AvatarView `form:"view" binding:"omitempty,oneof=face bust body"`
+	Size     int              `form:"size" binding:"omitempty,min=8,max=512"`
+}
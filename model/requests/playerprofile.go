@@ -0,0 +1,5 @@
+package requests
+
+type PlayerProfileRequest struct {
+	PlayerId string `uri:"id" binding:"required,uuid"`
+}
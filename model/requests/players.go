@@ -0,0 +1,13 @@
+package requests
+
+import "com.oykdn.mc-advancement-collector/model"
+
+type PlayersRequest struct {
+	Name    string           `form:"name"`
+	Summary bool             `form:"summary"`
+	Sort    model.PlayerSort `form:"sort" binding:"omitempty,oneof=name progress activity"`
+	Order   model.SortOrder  `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit   int              `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Offset  int              `form:"offset" binding:"omitempty,min=0"`
+	Lang    string           `form:"lang"`
+}
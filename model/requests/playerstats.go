@@ -0,0 +1,28 @@
+package requests
+
+import "strings"
+
+type PlayerStatsRequest struct {
+	PlayerId string `uri:"id" binding:"required,uuid"`
+	Category string `form:"category"`
+	Lang     string `form:"lang"`
+}
+
+// Categories はカンマ区切りで指定された統計カテゴリを返す
+// - 名前空間を省略した場合は minecraft: とみなす
+func (r PlayerStatsRequest) Categories() []string {
+	var categories []string
+	for _, category := range strings.Split(r.Category, ",") {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+
+		if !strings.Contains(category, ":") {
+			category = "minecraft:" + category
+		}
+		categories = append(categories, category)
+	}
+
+	return categories
+}
@@ -0,0 +1,10 @@
+package responses
+
+import "com.oykdn.mc-advancement-collector/model"
+
+type AdvancementHistoryResponse struct {
+	Events []model.AdvancementHistoryEvent `json:"events"`
+	Total  int                             `json:"total"`
+	Limit  int                             `json:"limit"`
+	Offset int                             `json:"offset"`
+}
@@ -0,0 +1,13 @@
+package responses
+
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+type AdvancementStatsResponse struct {
+	Advancements []model.AdvancementStats `json:"advancements"`
+	Players      int                      `json:"players"`
+	Updated      time.Time                `json:"updated"`
+}
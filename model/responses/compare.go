@@ -0,0 +1,8 @@
+package responses
+
+import "com.oykdn.mc-advancement-collector/model"
+
+type CompareResponse struct {
+	Players      []model.PlayerComparison      `json:"players"`
+	Advancements []model.AdvancementComparison `json:"advancements"`
+}
@@ -0,0 +1,13 @@
+package responses
+
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+type DiagnosticsResponse struct {
+	UnknownCriteria []model.CriterionDiagnostic `json:"unknownCriteria"`
+	Players         int                         `json:"players"`
+	Updated         time.Time                   `json:"updated"`
+}
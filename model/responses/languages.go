@@ -0,0 +1,38 @@
+package responses
+
+import "com.oykdn.mc-advancement-collector/lang"
+
+type LanguagesResponse struct {
+	Default   string          `json:"default"`
+	Fallback  []string        `json:"fallback"`
+	Languages []LanguageEntry `json:"languages"`
+}
+
+type LanguageEntry struct {
+	Code   string `json:"code"`
+	Name   string `json:"name,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+func ConvertToLanguagesResponse(languages *lang.Languages) *LanguagesResponse {
+	entries := make([]LanguageEntry, 0, len(languages.Codes()))
+	for _, code := range languages.Codes() {
+		name, region := languages.Get(code).Name()
+		entries = append(entries, LanguageEntry{
+			Code:   code,
+			Name:   name,
+			Region: region,
+		})
+	}
+
+	fallback := languages.Fallback
+	if fallback == nil {
+		fallback = []string{}
+	}
+
+	return &LanguagesResponse{
+		Default:   languages.Default,
+		Fallback:  fallback,
+		Languages: entries,
+	}
+}
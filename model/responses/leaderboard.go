@@ -0,0 +1,15 @@
+package responses
+
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+type LeaderboardResponse struct {
+	Ranking []model.LeaderboardEntry `json:"ranking"`
+	Total   int                      `json:"total"`
+	Limit   int                      `json:"limit"`
+	Offset  int                      `json:"offset"`
+	Updated time.Time                `json:"updated"`
+}
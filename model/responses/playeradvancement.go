@@ -7,8 +7,17 @@ import (
 )
 
 type PlayerAdvancementResponse struct {
-	Advancements []*model.PlayerAdvancement `json:"advancements"`
-	Progress     model.AdvancementProgress  `json:"progress"`
-	Updated      time.Time                  `json:"updated"`
-	Cached       time.Time                  `json:"cached"`
+	Advancements []*model.PlayerAdvancement           `json:"advancements"`
+	Progress     model.AdvancementProgress            `json:"progress"`
+	Tabs         map[string]model.AdvancementProgress `json:"tabs"`
+	Updated      time.Time                            `json:"updated"`
+	Cached       time.Time                            `json:"cached"`
+}
+
+type PlayerAdvancementTreeResponse struct {
+	Tree     []*model.PlayerAdvancementNode       `json:"tree"`
+	Progress model.AdvancementProgress            `json:"progress"`
+	Tabs     map[string]model.AdvancementProgress `json:"tabs"`
+	Updated  time.Time                            `json:"updated"`
+	Cached   time.Time                            `json:"cached"`
 }
@@ -3,5 +3,8 @@ package responses
 import "com.oykdn.mc-advancement-collector/model"
 
 type PlayersResponse struct {
-	Players []model.PlayerProfile `json:"players"`
+	Players []model.PlayerListEntry `json:"players"`
+	Total   int                     `json:"total"`
+	Limit   int                     `json:"limit,omitempty"`
+	Offset  int                     `json:"offset"`
 }
@@ -0,0 +1,13 @@
+package responses
+
+import (
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+type PlayerStatsResponse struct {
+	Categories []*model.PlayerStatsCategory `json:"categories"`
+	Updated    time.Time                    `json:"updated"`
+	Cached     time.Time                    `json:"cached"`
+}
@@ -0,0 +1,76 @@
+package nbt
+
+// 型を確認しながら値を取り出す
+// - 数値はNBT上の型が異なっていても変換して返す (バージョンによって型が変わるため)
+
+func (c Compound) Compound(key string) (Compound, bool) {
+	v, ok := c[key].(Compound)
+	return v, ok
+}
+
+func (c Compound) List(key string) ([]interface{}, bool) {
+	v, ok := c[key].([]interface{})
+	return v, ok
+}
+
+func (c Compound) String(key string) (string, bool) {
+	v, ok := c[key].(string)
+	return v, ok
+}
+
+func (c Compound) Int(key string) (int64, bool) {
+	return toInt(c[key])
+}
+
+func (c Compound) Float(key string) (float64, bool) {
+	return toFloat(c[key])
+}
+
+// Floats は数値のリスト (Pos, Rotation等) を返す
+func (c Compound) Floats(key string) ([]float64, bool) {
+	list, ok := c.List(key)
+	if !ok {
+		return nil, false
+	}
+
+	v := make([]float64, 0, len(list))
+	for _, e := range list {
+		f, ok := toFloat(e)
+		if !ok {
+			return nil, false
+		}
+		v = append(v, f)
+	}
+
+	return v, true
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	if i, ok := toInt(v); ok {
+		return float64(i), true
+	}
+
+	return 0, false
+}
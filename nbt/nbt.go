@@ -0,0 +1,273 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+type TagType byte
+
+const (
+	TagEnd TagType = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+const (
+	// 壊れたファイルで巨大な確保をしないための上限
+	maxArrayLength = 1 << 24
+	maxDepth       = 512
+)
+
+var (
+	ErrInvalidTag = fmt.Errorf("invalid nbt tag")
+	ErrRootType   = fmt.Errorf("nbt root is not a compound")
+	ErrTooLarge   = fmt.Errorf("nbt array too large")
+	ErrTooDeep    = fmt.Errorf("nbt nesting too deep")
+)
+
+// Compound はTAG_Compoundを表す
+// 値は以下の型で保持する
+//   - TAG_Byte: int8, TAG_Short: int16, TAG_Int: int32, TAG_Long: int64
+//   - TAG_Float: float32, TAG_Double: float64, TAG_String: string
+//   - TAG_Byte_Array: []int8, TAG_Int_Array: []int32, TAG_Long_Array: []int64
+//   - TAG_List: []interface{}, TAG_Compound: Compound
+type Compound map[string]interface{}
+
+// ReadFile はNBTファイルを読み込む (gzip圧縮の有無は自動判定)
+func ReadFile(path string) (string, Compound, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read はルートのTAG_Compoundを読み込み、名前と値を返す
+func Read(r io.Reader) (string, Compound, error) {
+	br := bufio.NewReader(r)
+
+	// gzipのマジックナンバー (1f 8b) で圧縮を判定
+	magic, err := br.Peek(2)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var src io.Reader = br
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return "", nil, err
+		}
+		defer gz.Close()
+
+		src = gz
+	}
+
+	d := &decoder{r: bufio.NewReader(src)}
+
+	tag, err := d.tagType()
+	if err != nil {
+		return "", nil, err
+	}
+	if tag != TagCompound {
+		return "", nil, ErrRootType
+	}
+
+	name, err := d.string()
+	if err != nil {
+		return "", nil, err
+	}
+
+	v, err := d.payload(TagCompound, 0)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, v.(Compound), nil
+}
+
+type decoder struct {
+	r *bufio.Reader
+}
+
+func (d *decoder) tagType() (TagType, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return TagEnd, err
+	}
+
+	if TagType(b) > TagLongArray {
+		return TagEnd, fmt.Errorf("%w: %d", ErrInvalidTag, b)
+	}
+
+	return TagType(b), nil
+}
+
+func (d *decoder) read(v interface{}) error {
+	return binary.Read(d.r, binary.BigEndian, v)
+}
+
+func (d *decoder) length() (int, error) {
+	var n int32
+	if err := d.read(&n); err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, nil
+	}
+	if n > maxArrayLength {
+		return 0, ErrTooLarge
+	}
+
+	return int(n), nil
+}
+
+// string はJavaの修正UTF-8で書かれた文字列を読む (BMP内の文字はUTF-8と同じ)
+func (d *decoder) string() (string, error) {
+	var n uint16
+	if err := d.read(&n); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (d *decoder) payload(tag TagType, depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, ErrTooDeep
+	}
+
+	switch tag {
+	case TagByte:
+		var v int8
+		err := d.read(&v)
+		return v, err
+
+	case TagShort:
+		var v int16
+		err := d.read(&v)
+		return v, err
+
+	case TagInt:
+		var v int32
+		err := d.read(&v)
+		return v, err
+
+	case TagLong:
+		var v int64
+		err := d.read(&v)
+		return v, err
+
+	case TagFloat:
+		var v uint32
+		err := d.read(&v)
+		return math.Float32frombits(v), err
+
+	case TagDouble:
+		var v uint64
+		err := d.read(&v)
+		return math.Float64frombits(v), err
+
+	case TagByteArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int8, n)
+		err = d.read(v)
+		return v, err
+
+	case TagString:
+		return d.string()
+
+	case TagList:
+		elem, err := d.tagType()
+		if err != nil {
+			return nil, err
+		}
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+
+		v := make([]interface{}, 0, n)
+		if elem == TagEnd {
+			return v, nil
+		}
+		for i := 0; i < n; i++ {
+			e, err := d.payload(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, e)
+		}
+		return v, nil
+
+	case TagCompound:
+		v := make(Compound)
+		for {
+			t, err := d.tagType()
+			if err != nil {
+				return nil, err
+			}
+			if t == TagEnd {
+				return v, nil
+			}
+
+			name, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+
+			e, err := d.payload(t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			v[name] = e
+		}
+
+	case TagIntArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int32, n)
+		err = d.read(v)
+		return v, err
+
+	case TagLongArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		v := make([]int64, n)
+		err = d.read(v)
+		return v, err
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrInvalidTag, tag)
+}
@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	offlinePlayerPrefix = "OfflinePlayer:"
+
+	// Floodgate (Geyser) のUUIDは上位64bitが0、下位64bitがXUID
+	floodgateUUIDPrefix = "00000000-0000-0000-"
+)
+
+// AccountTypeOf はUUIDの形式からアカウントの種類を判定する
+func AccountTypeOf(id string) model.AccountType {
+	id = strings.ToLower(id)
+	if len(id) != 36 {
+		return model.AccountUnknown
+	}
+
+	if strings.HasPrefix(id, floodgateUUIDPrefix) {
+		return model.AccountFloodgate
+	}
+
+	// バージョンは3つ目のブロックの先頭
+	switch id[14] {
+	case '3':
+		return model.AccountOffline
+	case '4':
+		return model.AccountOnline
+	}
+
+	return model.AccountUnknown
+}
+
+// OfflineUUID はオフラインモードのサーバーが名前から生成するUUID (v3) を返す
+// - Javaの UUID.nameUUIDFromBytes("OfflinePlayer:" + name) と同じ
+func OfflineUUID(name string) string {
+	sum := md5.Sum([]byte(offlinePlayerPrefix + name))
+	sum[6] = sum[6]&0x0f | 0x30
+	sum[8] = sum[8]&0x3f | 0x80
+
+	return FormatUUID(hex.EncodeToString(sum[:]))
+}
+
+// FloodgateXUID はFloodgateのUUIDからXUID (10進数) を取り出す
+func FloodgateXUID(id string) (string, bool) {
+	id = strings.ToLower(id)
+	if AccountTypeOf(id) != model.AccountFloodgate {
+		return "", false
+	}
+
+	xuid, err := strconv.ParseUint(strings.ReplaceAll(id[len(floodgateUUIDPrefix):], "-", ""), 16, 64)
+	if err != nil {
+		return "", false
+	}
+
+	return strconv.FormatUint(xuid, 10), true
+}
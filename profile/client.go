@@ -0,0 +1,116 @@
+package profile
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultRetries = 3
+	DefaultBackoff = 500 * time.Millisecond
+
+	maxBackoff = 30 * time.Second
+)
+
+// Client はレート制限と再試行を行うHTTPクライアント
+// - 429 / 5xx / 通信エラーは指数バックオフで再試行する (Retry-After があれば従う)
+type Client struct {
+	client  *http.Client
+	limiter *rate.Limiter
+	retries int
+	backoff time.Duration
+}
+
+// NewClient は limiter が nil の場合はレート制限をしない
+func NewClient(client *http.Client, limiter *rate.Limiter, retries int) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if retries < 0 {
+		retries = 0
+	}
+
+	return &Client{
+		client:  client,
+		limiter: limiter,
+		retries: retries,
+		backoff: DefaultBackoff,
+	}
+}
+
+func (c *Client) Get(url string) (*http.Response, error) {
+	return c.do(http.MethodGet, url, "", nil)
+}
+
+func (c *Client) Post(url, contentType string, body []byte) (*http.Response, error) {
+	return c.do(http.MethodPost, url, contentType, body)
+}
+
+func (c *Client) do(method, url, contentType string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(context.Background()); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequest(method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := c.client.Do(req)
+		if err == nil && !retryable(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt >= c.retries {
+			return resp, err
+		}
+
+		wait := c.backoff << attempt
+		if resp != nil {
+			if after := retryAfter(resp); after > 0 {
+				wait = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		// retries が大きい場合はシフトで桁あふれするため、負の値も上限にする
+		if wait <= 0 || wait > maxBackoff {
+			wait = maxBackoff
+		}
+
+		// 同時に失敗したリクエストが一斉に再試行しないよう揺らぎを入れる
+		time.Sleep(wait + time.Duration(rand.Int63n(int64(wait)/2+1)))
+	}
+}
+
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
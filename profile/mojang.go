@@ -0,0 +1,121 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	MojangSessionServer = "https://sessionserver.mojang.com"
+	MojangAPI           = "https://api.mojang.com"
+
+	BULK_PROFILE_API_PATH = "/profiles/minecraft"
+
+	// 一括APIで1回に問い合わせられる名前の数
+	BulkMaxNames = 10
+)
+
+var (
+	ErrBulkUnsupported = fmt.Errorf("bulk name lookup is not supported")
+)
+
+// BulkResolver は複数のプレイヤー名からまとめてUUIDを引く
+// - 戻り値のキーは小文字にした名前
+type BulkResolver interface {
+	ProfilesByName([]string) (map[string]*model.PlayerProfile, error)
+}
+
+// bulkSupporter は内包する Provider によって一括APIが使えるかが決まるもの (NegativeCache, Chain)
+type bulkSupporter interface {
+	SupportsBulk() bool
+}
+
+// SupportsBulk は Provider が一括APIで名前を引けるかを返す
+func SupportsBulk(p Provider) bool {
+	if _, ok := p.(BulkResolver); !ok {
+		return false
+	}
+	if s, ok := p.(bulkSupporter); ok {
+		return s.SupportsBulk()
+	}
+
+	return true
+}
+
+// Mojang はセッションサーバーに加え、名前->UUIDの一括APIに対応する
+type Mojang struct {
+	*SessionServer
+	apiURL string
+}
+
+func NewMojang(client *Client) *Mojang {
+	return NewMojangWithURL(MojangSessionServer, MojangAPI, client)
+}
+
+func NewMojangWithURL(sessionURL, apiURL string, client *Client) *Mojang {
+	s := NewSessionServer(ProviderMojang, sessionURL, client)
+
+	return &Mojang{
+		SessionServer: s,
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+	}
+}
+
+func (m *Mojang) ProfilesByName(names []string) (map[string]*model.PlayerProfile, error) {
+	profiles := make(map[string]*model.PlayerProfile, len(names))
+
+	for start := 0; start < len(names); start += BulkMaxNames {
+		end := start + BulkMaxNames
+		if end > len(names) {
+			end = len(names)
+		}
+
+		body, err := json.Marshal(names[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := m.client.Post(m.apiURL+BULK_PROFILE_API_PATH, "application/json", body)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: unexpected status %d", m.name, resp.StatusCode)
+		}
+
+		var found []model.MojangPlayerProfile
+		if err := json.Unmarshal(b, &found); err != nil {
+			return nil, err
+		}
+
+		for _, p := range found {
+			profiles[strings.ToLower(p.Name)] = &model.PlayerProfile{
+				Id:   FormatUUID(p.Id),
+				Name: p.Name,
+			}
+		}
+	}
+
+	return profiles, nil
+}
+
+// FormatUUID はハイフン無しのUUIDをハイフン区切りにする
+func FormatUUID(id string) string {
+	id = strings.ToLower(id)
+	if len(id) != 32 {
+		return id
+	}
+
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+}
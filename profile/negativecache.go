@@ -0,0 +1,72 @@
+package profile
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	DefaultNegativeCacheTTL = 1 * time.Hour
+)
+
+// NegativeCache は存在しなかったUUIDを一定時間覚え、その間は問い合わせない
+type NegativeCache struct {
+	Provider
+	ttl time.Duration
+
+	mu      sync.Mutex
+	unknown map[string]time.Time
+}
+
+func NewNegativeCache(provider Provider, ttl time.Duration) *NegativeCache {
+	if ttl <= 0 {
+		ttl = DefaultNegativeCacheTTL
+	}
+
+	return &NegativeCache{
+		Provider: provider,
+		ttl:      ttl,
+		unknown:  make(map[string]time.Time),
+	}
+}
+
+func (n *NegativeCache) Profile(id string) (*model.PlayerProfile, error) {
+	key := normalizeId(id)
+
+	n.mu.Lock()
+	expires, exists := n.unknown[key]
+	if exists && time.Now().After(expires) {
+		delete(n.unknown, key)
+		exists = false
+	}
+	n.mu.Unlock()
+
+	if exists {
+		return nil, ErrProfileNotFound
+	}
+
+	profile, err := n.Provider.Profile(id)
+	if errors.Is(err, ErrProfileNotFound) {
+		n.mu.Lock()
+		n.unknown[key] = time.Now().Add(n.ttl)
+		n.mu.Unlock()
+	}
+
+	return profile, err
+}
+
+// SupportsBulk は内包する Provider が一括APIに対応しているかを返す
+func (n *NegativeCache) SupportsBulk() bool {
+	return SupportsBulk(n.Provider)
+}
+
+func (n *NegativeCache) ProfilesByName(names []string) (map[string]*model.PlayerProfile, error) {
+	if SupportsBulk(n.Provider) {
+		return n.Provider.(BulkResolver).ProfilesByName(names)
+	}
+
+	return nil, ErrBulkUnsupported
+}
@@ -0,0 +1,151 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"com.oykdn.mc-advancement-collector/config"
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	ProviderMojang    = "mojang"
+	ProviderYggdrasil = "yggdrasil"
+	ProviderStatic    = "static"
+
+	DefaultTimeout = 10 * time.Second
+
+	// 1秒あたりのリクエスト数 (Mojang API の 429 を避ける)
+	DefaultRateLimit = 5
+	DefaultBurst     = 10
+)
+
+var (
+	ErrProfileNotFound   = fmt.Errorf("profile not found")
+	ErrUnknownProvider   = fmt.Errorf("unknown profile provider")
+	ErrProviderNoBaseURL = fmt.Errorf("profile provider url is required")
+)
+
+// Provider はUUIDからプレイヤーのプロフィールを取得する
+// - 存在しないプレイヤーの場合は ErrProfileNotFound を返す
+type Provider interface {
+	Name() string
+	Profile(id string) (*model.PlayerProfile, error)
+}
+
+// Chain は設定された順にプロフィールを問い合わせ、最初に見つかったものを返す
+type Chain []Provider
+
+func (c Chain) Name() string {
+	return "chain"
+}
+
+// SupportsBulk は一括APIに対応した Provider を含むかを返す
+func (c Chain) SupportsBulk() bool {
+	for _, p := range c {
+		if SupportsBulk(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProfilesByName は一括APIに対応した最初の Provider で問い合わせる
+func (c Chain) ProfilesByName(names []string) (map[string]*model.PlayerProfile, error) {
+	for _, p := range c {
+		if SupportsBulk(p) {
+			return p.(BulkResolver).ProfilesByName(names)
+		}
+	}
+
+	return nil, ErrBulkUnsupported
+}
+
+func (c Chain) Profile(id string) (*model.PlayerProfile, error) {
+	var errs []error
+	for _, p := range c {
+		profile, err := p.Profile(id)
+		if err == nil {
+			return profile, nil
+		}
+
+		if !errors.Is(err, ErrProfileNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+	}
+
+	// 全て見つからなかった場合のみ ErrProfileNotFound
+	if len(errs) == 0 {
+		return nil, ErrProfileNotFound
+	}
+	return nil, errors.Join(errs...)
+}
+
+// NewFromConfig は config.yml の players.remote から Provider を組み立てる
+// - providers の指定が無い場合は Mojang API のみ
+// - 全ての Provider で共通のレート制限・再試行・存在しないUUIDのキャッシュを行う
+func NewFromConfig(conf config.AppConfigPlayersRemote) (Provider, error) {
+	timeout := DefaultTimeout
+	if conf.Timeout > 0 {
+		timeout = time.Duration(conf.Timeout) * time.Second
+	}
+
+	// 指定が無い場合も既定のレート制限を行う (負の値の場合のみ無制限)
+	var limiter *rate.Limiter
+	if conf.RateLimit >= 0 {
+		limit := conf.RateLimit
+		if limit == 0 {
+			limit = DefaultRateLimit
+		}
+		burst := conf.Burst
+		if burst <= 0 {
+			burst = DefaultBurst
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit), burst)
+	}
+
+	retries := DefaultRetries
+	if conf.Retries != nil {
+		retries = *conf.Retries
+	}
+
+	client := NewClient(&http.Client{Timeout: timeout}, limiter, retries)
+
+	providers := conf.Providers
+	if len(providers) == 0 {
+		providers = []config.AppConfigProfileProvider{
+			{Type: ProviderMojang},
+		}
+	}
+
+	var chain Chain
+	for _, p := range providers {
+		switch p.Type {
+		case ProviderMojang:
+			chain = append(chain, NewMojang(client))
+
+		case ProviderYggdrasil:
+			if p.Url == "" {
+				return nil, fmt.Errorf("%w: %s", ErrProviderNoBaseURL, p.Type)
+			}
+			chain = append(chain, NewSessionServer(ProviderYggdrasil, p.Url, client))
+
+		case ProviderStatic:
+			static, err := LoadStatic(p.Path)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, static)
+
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, p.Type)
+		}
+	}
+
+	return NewNegativeCache(chain, time.Duration(conf.NegativeCacheTTL)*time.Second), nil
+}
@@ -0,0 +1,201 @@
+package profile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	testId       = "069a79f4-44e9-4726-a5be-fca90e38aaf5"
+	testUnsigned = "069a79f444e94726a5befca90e38aaf5"
+)
+
+// newTestClient は再試行の待ち時間を短くしたクライアントを返す
+func newTestClient(retries int) *Client {
+	c := NewClient(nil, nil, retries)
+	c.backoff = time.Millisecond
+	return c
+}
+
+func TestSessionServerProfile(t *testing.T) {
+	textures, _ := json.Marshal(map[string]interface{}{
+		"textures": map[string]interface{}{
+			"SKIN": map[string]interface{}{
+				"url":      "http://textures.minecraft.net/texture/abc",
+				"metadata": map[string]string{"model": "slim"},
+			},
+		},
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PROFILE_API_PATH + "/" + testUnsigned:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":   testUnsigned,
+				"name": "Notch",
+				"properties": []map[string]string{
+					{"name": "textures", "value": base64.StdEncoding.EncodeToString(textures)},
+				},
+			})
+		case PROFILE_API_PATH + "/00000000000000000000000000000204":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewSessionServer(ProviderYggdrasil, srv.URL, newTestClient(0))
+
+	p, err := s.Profile(testId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Id != testId || p.Name != "Notch" {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+	if p.Skin == nil || p.Skin.Url != "http://textures.minecraft.net/texture/abc" || !p.Skin.Slim {
+		t.Errorf("unexpected skin: %+v", p.Skin)
+	}
+
+	for _, id := range []string{
+		"00000000-0000-0000-0000-000000000204",
+		"00000000-0000-0000-0000-000000000404",
+	} {
+		if _, err := s.Profile(id); !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("%s: expected ErrProfileNotFound, got %v", id, err)
+		}
+	}
+}
+
+func TestMojangProfilesByName(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != BULK_PROFILE_API_PATH {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+
+		var names []string
+		if err := json.NewDecoder(r.Body).Decode(&names); err != nil || len(names) > BulkMaxNames {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// 存在するのは Notch のみ
+		var found []map[string]string
+		for _, name := range names {
+			if strings.EqualFold(name, "notch") {
+				found = append(found, map[string]string{"id": testUnsigned, "name": "Notch"})
+			}
+		}
+		json.NewEncoder(w).Encode(found)
+	}))
+	defer srv.Close()
+
+	m := NewMojangWithURL(srv.URL, srv.URL, newTestClient(0))
+
+	names := []string{"notch"}
+	for i := 0; i < BulkMaxNames; i++ {
+		names = append(names, "unknown")
+	}
+
+	found, err := m.ProfilesByName(names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+	if p, exists := found["notch"]; !exists || p.Id != testId || p.Name != "Notch" {
+		t.Errorf("unexpected result: %+v", found)
+	}
+	if len(found) != 1 {
+		t.Errorf("expected 1 profile, got %d", len(found))
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": testUnsigned, "name": "Notch"})
+	}))
+	defer srv.Close()
+
+	// 再試行で成功する
+	p, err := NewSessionServer(ProviderMojang, srv.URL, newTestClient(3)).Profile(testId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Notch" || atomic.LoadInt32(&attempts) != 3 {
+		t.Errorf("unexpected result: %+v after %d attempts", p, attempts)
+	}
+
+	// 再試行の回数を超えた場合は最後のレスポンスのエラー
+	atomic.StoreInt32(&attempts, 0)
+	if _, err := NewSessionServer(ProviderMojang, srv.URL, newTestClient(1)).Profile(testId); err == nil || errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected status error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"invalid", 0},
+	} {
+		resp := &http.Response{Header: http.Header{}}
+		if tc.header != "" {
+			resp.Header.Set("Retry-After", tc.header)
+		}
+
+		if got := retryAfter(resp); got != tc.want {
+			t.Errorf("Retry-After %q: expected %v, got %v", tc.header, tc.want, got)
+		}
+	}
+
+	// HTTP-date の場合は現在からの時間
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if got := retryAfter(resp); got <= 59*time.Minute || got > time.Hour {
+		t.Errorf("unexpected Retry-After date: %v", got)
+	}
+}
+
+func TestSupportsBulk(t *testing.T) {
+	mojang := NewMojangWithURL("http://127.0.0.1", "http://127.0.0.1", nil)
+	yggdrasil := NewSessionServer(ProviderYggdrasil, "http://127.0.0.1", nil)
+
+	for _, tc := range []struct {
+		name     string
+		provider Provider
+		want     bool
+	}{
+		{"mojang", mojang, true},
+		{"yggdrasil", yggdrasil, false},
+		{"chain without mojang", NewNegativeCache(Chain{yggdrasil}, 0), false},
+		{"chain with mojang", NewNegativeCache(Chain{yggdrasil, mojang}, 0), true},
+	} {
+		if got := SupportsBulk(tc.provider); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	PROFILE_API_PATH = "/session/minecraft/profile"
+)
+
+// SessionServer はMojangのセッションサーバーと互換のAPIからプロフィールを取得する
+// - authlib-injector (Ely.by, Blessing Skin 等) は sessionserver のURLを指定する
+type SessionServer struct {
+	name    string
+	baseURL string
+	client  *Client
+}
+
+func NewSessionServer(name, baseURL string, client *Client) *SessionServer {
+	if client == nil {
+		client = NewClient(nil, nil, 0)
+	}
+
+	return &SessionServer{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
+
+func (s *SessionServer) Name() string {
+	return s.name
+}
+
+func (s *SessionServer) Profile(id string) (*model.PlayerProfile, error) {
+	// Yggdrasil の仕様に合わせ、ハイフン無しのUUIDで問い合わせる
+	resp, err := s.client.Get(fmt.Sprintf("%s%s/%s", s.baseURL, PROFILE_API_PATH, strings.ReplaceAll(id, "-", "")))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		break
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, ErrProfileNotFound
+	default:
+		return nil, fmt.Errorf("%s: unexpected status %d", s.name, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile model.MojangPlayerProfile
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return nil, err
+	}
+
+	// スキンが読めなくてもプロフィールとしては使う
+	skin, _ := decodeSkin(profile.Properties)
+
+	return &model.PlayerProfile{
+		Id:   FormatUUID(profile.Id),
+		Name: profile.Name,
+		Skin: skin,
+	}, nil
+}
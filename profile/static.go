@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+// Static はYAMLに書かれたUUIDと名前の対応からプロフィールを返す
+//
+//	players:
+//	  <uuid>: <name>
+type Static struct {
+	Players map[string]string `yaml:"players"`
+}
+
+func LoadStatic(path string) (*Static, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Static
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+
+	// ハイフンの有無・大文字小文字を区別しない
+	players := make(map[string]string, len(s.Players))
+	for id, name := range s.Players {
+		players[normalizeId(id)] = name
+	}
+	s.Players = players
+
+	return &s, nil
+}
+
+func (s *Static) Name() string {
+	return ProviderStatic
+}
+
+func (s *Static) Profile(id string) (*model.PlayerProfile, error) {
+	name, exists := s.Players[normalizeId(id)]
+	if !exists {
+		return nil, ErrProfileNotFound
+	}
+
+	return &model.PlayerProfile{
+		Id:   id,
+		Name: name,
+	}, nil
+}
+
+func normalizeId(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
@@ -0,0 +1,46 @@
+package profile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"com.oykdn.mc-advancement-collector/model"
+)
+
+const (
+	texturesProperty = "textures"
+	textureSkin      = "SKIN"
+	skinModelSlim    = "slim"
+)
+
+// decodeSkin は textures プロパティからスキンのURLとモデルを取り出す
+// - スキンが設定されていない場合は nil
+func decodeSkin(properties []model.MojangProfileProperty) (*model.PlayerSkin, error) {
+	for _, p := range properties {
+		if p.Name != texturesProperty {
+			continue
+		}
+
+		b, err := base64.StdEncoding.DecodeString(p.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		var textures model.MojangTextures
+		if err := json.Unmarshal(b, &textures); err != nil {
+			return nil, err
+		}
+
+		skin, exists := textures.Textures[textureSkin]
+		if !exists || skin.Url == "" {
+			return nil, nil
+		}
+
+		return &model.PlayerSkin{
+			Url:  skin.Url,
+			Slim: skin.Metadata.Model == skinModelSlim,
+		}, nil
+	}
+
+	return nil, nil
+}
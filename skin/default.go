@@ -0,0 +1,103 @@
+package skin
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// palette は既定スキンの配色
+// - ゲームのテクスチャは同梱できないため、Steve / Alex に似せた単色の塗り分けで描く
+type palette struct {
+	skin, hair, eye, mouth, shirt, pants, shoes color.NRGBA
+}
+
+var (
+	steve = palette{
+		skin:  color.NRGBA{0xb4, 0x84, 0x6d, 0xff},
+		hair:  color.NRGBA{0x2b, 0x1e, 0x0d, 0xff},
+		eye:   color.NRGBA{0x49, 0x4a, 0x8f, 0xff},
+		mouth: color.NRGBA{0x6a, 0x40, 0x30, 0xff},
+		shirt: color.NRGBA{0x00, 0xa8, 0xa8, 0xff},
+		pants: color.NRGBA{0x3a, 0x31, 0x89, 0xff},
+		shoes: color.NRGBA{0x6b, 0x6b, 0x6b, 0xff},
+	}
+	alex = palette{
+		skin:  color.NRGBA{0xf1, 0xc3, 0x9a, 0xff},
+		hair:  color.NRGBA{0xe0, 0x7a, 0x2c, 0xff},
+		eye:   color.NRGBA{0x3d, 0x7a, 0x34, 0xff},
+		mouth: color.NRGBA{0xc8, 0x8a, 0x6e, 0xff},
+		shirt: color.NRGBA{0x6a, 0x9f, 0x3e, 0xff},
+		pants: color.NRGBA{0x5b, 0x40, 0x30, 0xff},
+		shoes: color.NRGBA{0x4a, 0x3a, 0x2e, 0xff},
+	}
+
+	defaultSteve = steve.render(false)
+	defaultAlex  = alex.render(true)
+)
+
+// Default はスキンが無いプレイヤーの既定スキンを返す
+// - ゲームと同じく UUID#hashCode の偶奇で Steve / Alex を選ぶ
+func Default(id string) (image.Image, bool) {
+	if slimByDefault(id) {
+		return defaultAlex, true
+	}
+	return defaultSteve, false
+}
+
+func slimByDefault(id string) bool {
+	b, err := hex.DecodeString(strings.ReplaceAll(id, "-", ""))
+	if err != nil || len(b) != 16 {
+		return false
+	}
+
+	hilo := binary.BigEndian.Uint64(b[:8]) ^ binary.BigEndian.Uint64(b[8:])
+	return (uint32(hilo>>32)^uint32(hilo))&1 == 1
+}
+
+func (p palette) render(slim bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+
+	fill := func(r image.Rectangle, c color.NRGBA) {
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	// 頭 (上面と側面の上部は髪)
+	fill(image.Rect(0, 0, 32, 16), p.skin)
+	fill(image.Rect(8, 0, 16, 8), p.hair)
+	fill(image.Rect(0, 8, 32, 10), p.hair)
+	fill(image.Rect(24, 8, 32, 16), p.hair)
+
+	// 顔
+	fill(image.Rect(9, 12, 11, 13), color.NRGBA{0xff, 0xff, 0xff, 0xff})
+	fill(image.Rect(10, 12, 11, 13), p.eye)
+	fill(image.Rect(13, 12, 15, 13), color.NRGBA{0xff, 0xff, 0xff, 0xff})
+	fill(image.Rect(13, 12, 14, 13), p.eye)
+	fill(image.Rect(11, 14, 13, 15), p.mouth)
+
+	// 胴体と腕 (袖の部分はシャツ)
+	fill(image.Rect(16, 16, 40, 32), p.shirt)
+	for _, r := range []image.Rectangle{image.Rect(40, 16, 56, 32), image.Rect(32, 48, 48, 64)} {
+		fill(r, p.skin)
+		fill(image.Rect(r.Min.X, r.Min.Y+4, r.Max.X, r.Min.Y+8), p.shirt)
+		fill(image.Rect(r.Min.X+4, r.Min.Y, r.Min.X+8, r.Min.Y+4), p.shirt)
+	}
+	if slim {
+		// 腕の幅が3のため、余った列は透過にしておく
+		fill(image.Rect(50, 16, 56, 20), color.NRGBA{})
+		fill(image.Rect(54, 20, 56, 32), color.NRGBA{})
+		fill(image.Rect(42, 48, 48, 52), color.NRGBA{})
+		fill(image.Rect(46, 52, 48, 64), color.NRGBA{})
+	}
+
+	// 脚
+	for _, r := range []image.Rectangle{image.Rect(0, 16, 16, 32), image.Rect(16, 48, 32, 64)} {
+		fill(r, p.pants)
+		fill(image.Rect(r.Min.X, r.Max.Y-2, r.Max.X, r.Max.Y), p.shoes)
+	}
+
+	return img
+}
@@ -0,0 +1,195 @@
+package skin
+
+import (
+	"image"
+	"image/draw"
+)
+
+// スキンの各部位の位置 (64x64 のスキン上の座標, 正面のみ)
+// - 1.8 より前の 64x32 のスキンは左腕・左脚が無いため、右腕・右脚を反転して使う
+var (
+	head       = image.Rect(8, 8, 16, 16)
+	hat        = image.Rect(40, 8, 48, 16)
+	torso      = image.Rect(20, 20, 28, 32)
+	jacket     = image.Rect(20, 36, 28, 48)
+	rightLeg   = image.Rect(4, 20, 8, 32)
+	rightPants = image.Rect(4, 36, 8, 48)
+	leftLeg    = image.Rect(20, 52, 24, 64)
+	leftPants  = image.Rect(4, 52, 8, 64)
+)
+
+// 腕は Alex (slim) の場合は幅3
+func rightArm(slim bool) image.Rectangle    { return armRect(44, 20, slim) }
+func rightSleeve(slim bool) image.Rectangle { return armRect(44, 36, slim) }
+func leftArm(slim bool) image.Rectangle     { return armRect(36, 52, slim) }
+func leftSleeve(slim bool) image.Rectangle  { return armRect(52, 52, slim) }
+
+func armRect(x, y int, slim bool) image.Rectangle {
+	width := 4
+	if slim {
+		width = 3
+	}
+	return image.Rect(x, y, x+width, y+12)
+}
+
+// valid はスキンとして扱える大きさかを返す (64x64, 64x32 とその整数倍のHDスキン)
+func valid(b image.Rectangle) bool {
+	w, h := b.Dx(), b.Dy()
+	return w >= 64 && w%64 == 0 && (h == w || h == w/2)
+}
+
+// Face は顔に帽子レイヤーを重ね、size x size に拡大する
+func Face(skin image.Image, size int) image.Image {
+	r := newRenderer(skin)
+	canvas := r.canvas(8, 8)
+
+	r.base(canvas, head, image.Pt(0, 0), false)
+	r.hat(canvas, image.Pt(0, 0))
+
+	return scale(canvas, size, size)
+}
+
+// Body は正面から見た全身を、幅 size x 高さ size*2 に拡大する
+func Body(skin image.Image, slim bool, size int) image.Image {
+	return scale(newRenderer(skin).body(slim), size, size*2)
+}
+
+// Bust は正面から見た胸から上を、size x size に拡大する
+func Bust(skin image.Image, slim bool, size int) image.Image {
+	body := newRenderer(skin).body(slim)
+	bust := body.SubImage(image.Rect(0, 0, body.Bounds().Dx(), body.Bounds().Dx())).(*image.RGBA)
+
+	return scale(bust, size, size)
+}
+
+type renderer struct {
+	skin   image.Image
+	unit   int
+	legacy bool
+}
+
+func newRenderer(skin image.Image) *renderer {
+	b := skin.Bounds()
+
+	return &renderer{
+		skin:   skin,
+		unit:   b.Dx() / 64,
+		legacy: b.Dy() == b.Dx()/2,
+	}
+}
+
+func (r *renderer) canvas(w, h int) *image.RGBA {
+	return image.NewRGBA(image.Rect(0, 0, w*r.unit, h*r.unit))
+}
+
+// body は 16x32 の全身の画像を返す
+func (r *renderer) body(slim bool) *image.RGBA {
+	canvas := r.canvas(16, 32)
+
+	armWidth := rightArm(slim).Dx()
+
+	r.base(canvas, head, image.Pt(4, 0), false)
+	r.hat(canvas, image.Pt(4, 0))
+
+	r.base(canvas, torso, image.Pt(4, 8), false)
+	r.base(canvas, rightLeg, image.Pt(4, 20), false)
+	r.base(canvas, rightArm(slim), image.Pt(4-armWidth, 8), false)
+	if r.legacy {
+		r.base(canvas, rightLeg, image.Pt(8, 20), true)
+		r.base(canvas, rightArm(slim), image.Pt(12, 8), true)
+		return canvas
+	}
+	r.base(canvas, leftLeg, image.Pt(8, 20), false)
+	r.base(canvas, leftArm(slim), image.Pt(12, 8), false)
+
+	r.overlay(canvas, jacket, image.Pt(4, 8))
+	r.overlay(canvas, rightPants, image.Pt(4, 20))
+	r.overlay(canvas, leftPants, image.Pt(8, 20))
+	r.overlay(canvas, rightSleeve(slim), image.Pt(4-armWidth, 8))
+	r.overlay(canvas, leftSleeve(slim), image.Pt(12, 8))
+
+	return canvas
+}
+
+// base は部位を不透明として描く (ゲーム内と同じくアルファを無視する)
+func (r *renderer) base(canvas *image.RGBA, part image.Rectangle, at image.Point, mirror bool) {
+	src := r.extract(part, mirror)
+	for i := range src.Pix {
+		if i%4 == 3 {
+			src.Pix[i] = 0xff
+		}
+	}
+
+	draw.Draw(canvas, r.dst(part, at), src, image.Point{}, draw.Src)
+}
+
+// overlay は透過を考慮して重ねる
+func (r *renderer) overlay(canvas *image.RGBA, part image.Rectangle, at image.Point) {
+	draw.Draw(canvas, r.dst(part, at), r.extract(part, false), image.Point{}, draw.Over)
+}
+
+// hat は帽子レイヤーを重ねる
+// - 旧形式のスキンで帽子が全て不透明な場合は、ゲーム内と同じく描かない
+func (r *renderer) hat(canvas *image.RGBA, at image.Point) {
+	if r.legacy && r.opaque(hat) {
+		return
+	}
+
+	r.overlay(canvas, hat, at)
+}
+
+func (r *renderer) opaque(part image.Rectangle) bool {
+	src := r.extract(part, false)
+	for i := 3; i < len(src.Pix); i += 4 {
+		if src.Pix[i] != 0xff {
+			return false
+		}
+	}
+
+	return true
+}
+
+// extract はスキン上の部位を切り出す (mirror の場合は左右反転)
+func (r *renderer) extract(part image.Rectangle, mirror bool) *image.NRGBA {
+	part = image.Rect(part.Min.X*r.unit, part.Min.Y*r.unit, part.Max.X*r.unit, part.Max.Y*r.unit).
+		Add(r.skin.Bounds().Min)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, part.Dx(), part.Dy()))
+	draw.Draw(dst, dst.Bounds(), r.skin, part.Min, draw.Src)
+
+	if mirror {
+		w := dst.Bounds().Dx()
+		for y := 0; y < dst.Bounds().Dy(); y++ {
+			for x := 0; x < w/2; x++ {
+				a, b := dst.NRGBAAt(x, y), dst.NRGBAAt(w-1-x, y)
+				dst.SetNRGBA(x, y, b)
+				dst.SetNRGBA(w-1-x, y, a)
+			}
+		}
+	}
+
+	return dst
+}
+
+func (r *renderer) dst(part image.Rectangle, at image.Point) image.Rectangle {
+	return image.Rectangle{
+		Min: at.Mul(r.unit),
+		Max: at.Add(part.Size()).Mul(r.unit),
+	}
+}
+
+// scale は最近傍補間で拡大・縮小する (ドット絵がぼやけないように)
+func scale(src *image.RGBA, w, h int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		sy := b.Min.Y + y*b.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := b.Min.X + x*b.Dx()/w
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+
+	return dst
+}
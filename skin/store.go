@@ -0,0 +1,137 @@
+package skin
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// スキンのPNGとして妥当な上限 (HDスキンを含む)
+	maxSkinBytes = 1 << 20
+)
+
+var (
+	ErrInvalidSkin = fmt.Errorf("invalid skin image")
+)
+
+// Store はスキンのPNGをダウンロードし、ディスクにキャッシュする
+// - テクスチャのURLは内容ごとに変わるため、キャッシュの失効は行わない
+// - 同じURLのダウンロードはまとめ、別のURLのダウンロードは並行して行う
+type Store struct {
+	dir    string
+	client *http.Client
+
+	group singleflight.Group
+	// ファイルの書き込みのみ排他する
+	mu sync.Mutex
+}
+
+func NewStore(dir string, client *http.Client) *Store {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Store{
+		dir:    dir,
+		client: client,
+	}
+}
+
+// Load はURLのスキンを返す (キャッシュが無い場合はダウンロードする)
+func (s *Store) Load(url string) (image.Image, error) {
+	sum := sha1.Sum([]byte(url))
+	filename := filepath.Join(s.dir, hex.EncodeToString(sum[:])+".png")
+
+	// 保存は rename で行うため、書き込み途中のファイルを読むことは無い
+	if f, err := os.Open(filename); err == nil {
+		defer f.Close()
+		return decode(f)
+	}
+
+	v, err, _ := s.group.Do(url, func() (interface{}, error) {
+		return s.download(url, filename)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(image.Image), nil
+}
+
+func (s *Store) download(url, filename string) (image.Image, error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("skin: unexpected status %d: %s", resp.StatusCode, url)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSkinBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	// 読めることを確認してから保存する
+	img, err := decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.save(filename, b); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+func (s *Store) save(filename string, b []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, "skin-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
+
+func decode(r io.Reader) (image.Image, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSkin, err)
+	}
+
+	if !valid(img.Bounds()) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSkin, img.Bounds().Size())
+	}
+
+	return img, nil
+}